@@ -0,0 +1,176 @@
+package goapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const defaultRequestIDHeader = "X-Request-ID"
+
+// longer or non printable request ids received from clients are replaced
+const maxRequestIDLength = 128
+
+// LogOptions configures access logging and request ids, zero values use defaults
+type LogOptions struct {
+	// Logger defaults to slog.Default()
+	Logger *slog.Logger
+	// Level of records of requests completed without server error, server errors are logged at error level
+	Level slog.Level
+	// RequestIDHeader is read and written with request id, defaults to X-Request-ID
+	RequestIDHeader string
+	// NewRequestID generates id of requests without one, defaults to 16 random bytes in hex
+	NewRequestID func() string
+}
+
+// AccessLog assigns or propagates request ids and logs one record per request
+func (api *API) AccessLog(opts LogOptions) {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+
+	if opts.RequestIDHeader == "" {
+		opts.RequestIDHeader = defaultRequestIDHeader
+	}
+
+	if opts.NewRequestID == nil {
+		opts.NewRequestID = newRequestID
+	}
+
+	api.accessLog = &opts
+}
+
+type requestInfoKey struct{}
+
+// requestInfo is shared by access log middleware and handlers of request
+type requestInfo struct {
+	id  string
+	err string
+}
+
+func requestInfoOf(ctx context.Context) *requestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(*requestInfo)
+	return info
+}
+
+// RequestID returns id of request, empty when access log is disabled
+func RequestID(ctx context.Context) string {
+	if info := requestInfoOf(ctx); info != nil {
+		return info.id
+	}
+
+	return ""
+}
+
+func newRequestID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// statusWriter records status and size of response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(data)
+	w.bytes += n
+	return n, err
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// withAccessLog logs requests of route, resolved per request so logging may be enabled after routes
+func withAccessLog(api *API, path string, endpointMethod *EndpointMethod, handle httprouter.Handle) httprouter.Handle {
+	method := strings.ToUpper(string(endpointMethod.Method))
+	operationId := endpointMethod.OperationId
+
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		opts := api.accessLog
+
+		if opts == nil {
+			handle(w, req, params)
+			return
+		}
+
+		start := time.Now()
+
+		id := req.Header.Get(opts.RequestIDHeader)
+		if !validRequestID(id) {
+			id = opts.NewRequestID()
+		}
+
+		info := &requestInfo{id: id}
+		req = req.WithContext(context.WithValue(req.Context(), requestInfoKey{}, info))
+
+		w.Header().Set(opts.RequestIDHeader, id)
+		recorder := &statusWriter{ResponseWriter: w}
+
+		handle(recorder, req, params)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		level := opts.Level
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		attrs := []slog.Attr{
+			slog.String("requestId", id),
+			slog.String("method", method),
+			slog.String("route", path),
+			slog.String("operationId", operationId),
+			slog.Int("status", status),
+			slog.Int("bytes", recorder.bytes),
+			slog.Duration("duration", time.Since(start)),
+		}
+
+		if span := SpanFromContext(req.Context()); span != nil {
+			attrs = append(attrs, slog.String("traceId", span.TraceID.String()))
+		}
+
+		if info.err != "" {
+			attrs = append(attrs, slog.String("error", info.err))
+		}
+
+		opts.Logger.LogAttrs(req.Context(), level, "request", attrs...)
+	}
+}
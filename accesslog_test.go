@@ -0,0 +1,63 @@
+package goapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type Trace struct {
+	RequestID string `json:"requestId"`
+}
+
+func TestAccessLog(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	appRouter.Get("/trace/:page", func(ctx context.Context, limit PageLimit) (Trace, goapi.APIError) {
+		return Trace{RequestID: goapi.RequestID(ctx)}, nil
+	}, goapi.RouteSpec{OperationId: "trace"})
+
+	var logs bytes.Buffer
+	api.AccessLog(goapi.LogOptions{Logger: slog.New(slog.NewJSONHandler(&logs, nil))})
+
+	req := httptest.NewRequest("GET", "/trace/1", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+
+	recorder := httptest.NewRecorder()
+	api.Handler().ServeHTTP(recorder, req)
+
+	var trace Trace
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &trace))
+	assert.Equal(t, "abc-123", trace.RequestID, "Request id not propagated")
+	assert.Equal(t, "abc-123", recorder.Header().Get("X-Request-ID"))
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	assert.Equal(t, "GET", record["method"])
+	assert.Equal(t, "/trace/:page", record["route"])
+	assert.Equal(t, "trace", record["operationId"])
+	assert.EqualValues(t, http.StatusOK, record["status"])
+	assert.EqualValues(t, recorder.Body.Len(), record["bytes"])
+
+	logs.Reset()
+	recorder = httptest.NewRecorder()
+	api.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/trace/1?limit=x", nil))
+
+	var apiError goapi.DefaultErrorType
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &apiError))
+	assert.Exactly(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.NotEmpty(t, apiError.RequestID, "Request id not assigned")
+	assert.Equal(t, recorder.Header().Get("X-Request-ID"), apiError.RequestID)
+
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	assert.Equal(t, apiError.Detail, record["error"])
+}
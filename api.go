@@ -48,6 +48,8 @@ type API struct {
 	errorIn      reflect.Type
 	errorOut     reflect.Type
 	errorScheme  string
+	// errorsAdapted is set by AdaptErrors
+	errorsAdapted bool
 
 	router       *httprouter.Router
 	Meta         AppMeta
@@ -56,6 +58,24 @@ type API struct {
 	Schemas      Schemas
 	SchemeGroups schemeGroups
 	Endpoints    Endpoints
+
+	// EnumComponents registers named enum types as reusable schemas
+	EnumComponents bool
+	// Body sets decoding of request bodies for routes not overriding it by RouteSpec
+	Body BodyOptions
+	// HealthTags are tags of routes registered by Health, set before calling it
+	HealthTags []string
+
+	accessLog *LogOptions
+	metrics   *metrics
+	exporter  SpanExporter
+	cors      *corsPolicy
+	preflight bool
+	contract  *contract
+
+	registrationErrors []error
+	startupHooks       []Hook
+	shutdownHooks      []Hook
 }
 
 func NewAPI[E error, T any](
@@ -65,25 +85,34 @@ func NewAPI[E error, T any](
 ) API {
 	api := API{
 		errorHandler: func(r Response, req *http.Request, err any) any {
-			return errorHandler(r, req, err.(E))
+			if e, ok := err.(E); ok {
+				return errorHandler(r, req, e)
+			}
+			// request errors raised by goapi, or errors of typed endpoints, when E cannot hold them
+			// and they are not adapted by AdaptErrors
+			return DefaultErrorHandler()(r, req, asAPIError(req, err))
 		},
 		Meta:   meta,
 		router: router,
 	}
 
-	schema, err := api.Schemas.RegisterSchema(GetType[T]())
-
-	if err != nil {
-		panic(err)
-	}
-
 	api.errorIn = GetType[E]()
 	api.errorOut = GetType[T]()
-	api.errorScheme = schema.Name
+
+	if schema, err := api.Schemas.RegisterSchema(GetType[T]()); err != nil {
+		api.addRegistrationError("", "", "", fmt.Errorf("invalid error schema: %w", err))
+	} else {
+		api.errorScheme = schema.Name
+	}
 
 	return api
 }
 
+// Handler returns http handler serving all registered routes
+func (api *API) Handler() http.Handler {
+	return api.router
+}
+
 func (api *API) Router() Router {
 	return Router{
 		api:    api,
@@ -91,10 +120,22 @@ func (api *API) Router() Router {
 	}
 }
 
+// Setup validates registered routes and generates openapi.yaml
 func (api *API) Setup() error {
+	if err := api.Validate(); err != nil {
+		return err
+	}
+
 	return generate(api)
 }
 
+// MustSetup is like Setup but panics on error
+func (api *API) MustSetup() {
+	if err := api.Setup(); err != nil {
+		panic(err)
+	}
+}
+
 func schemePrefix(scheme string) string {
 	runes := []rune(scheme)
 
@@ -0,0 +1,96 @@
+package goapi_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+)
+
+type UserID int64
+
+func (UserID) Spec() goapi.Spec {
+	return goapi.Spec{Name: "id", Required: true}
+}
+
+type Fields []string
+
+func (Fields) Spec() goapi.Spec {
+	return goapi.Spec{Name: "fields"}
+}
+
+type Verbose bool
+
+func (Verbose) Spec() goapi.Spec {
+	return goapi.Spec{Name: "verbose"}
+}
+
+type User struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name"`
+	Email  string   `json:"email"`
+	Fields []string `json:"fields"`
+}
+
+func GetUser(id UserID, fields Fields, verbose Verbose) (User, goapi.APIError) {
+	return User{ID: int64(id), Name: "john", Email: "john@example.com", Fields: fields}, nil
+}
+
+func CreateUser(r goapi.Response, user User) (User, goapi.APIError) {
+	r.Status = 201
+	return user, nil
+}
+
+func BenchmarkGetEndpoint(b *testing.B) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	handle := appRouter.Get("/users/:id", GetUser, goapi.RouteSpec{})
+
+	req := httptest.NewRequest("GET", "/users/42?fields=name&fields=email&verbose=true", nil)
+	params := httprouter.Params{{Key: "id", Value: "42"}}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		handle(httptest.NewRecorder(), req, params)
+	}
+}
+
+func BenchmarkPostEndpoint(b *testing.B) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	handle := appRouter.Post("/users", CreateUser, goapi.RouteSpec{})
+
+	body := []byte(`{"id":42,"name":"john","email":"john@example.com","fields":["name","email"]}`)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		req := httptest.NewRequest("POST", "/users", bytes.NewReader(body))
+		handle(httptest.NewRecorder(), req, httprouter.Params{})
+	}
+}
+
+func BenchmarkTypedGetEndpoint(b *testing.B) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	handle := goapi.Get(&appRouter, "/users/:id", func(ctx context.Context, in struct {
+		ID      UserID
+		Fields  Fields
+		Verbose Verbose
+	}) (User, error) {
+		return GetUser(in.ID, in.Fields, in.Verbose)
+	}, goapi.RouteSpec{})
+
+	req := httptest.NewRequest("GET", "/users/42?fields=name&fields=email&verbose=true", nil)
+	params := httprouter.Params{{Key: "id", Value: "42"}}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		handle(httptest.NewRecorder(), req, params)
+	}
+}
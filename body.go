@@ -0,0 +1,102 @@
+package goapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+)
+
+// BodyOptions controls decoding of json request bodies
+type BodyOptions struct {
+	// MaxBytes limits size of body, larger bodies are rejected with 413, 0 means no limit
+	MaxBytes int64
+	// DisallowUnknownFields rejects bodies with fields not declared by schema
+	DisallowUnknownFields bool
+	// SingleValue rejects data trailing the json value
+	SingleValue bool
+	// RequireFields rejects bodies missing fields tagged required with 422
+	RequireFields bool
+	// ContentTypes are accepted media types, other are rejected with 415, empty accepts any
+	ContentTypes []string
+}
+
+var errTrailingData = errors.New("unexpected data after json value")
+
+// MediaTypes returns media types documented for request body
+func (o BodyOptions) MediaTypes() []string {
+	if len(o.ContentTypes) == 0 {
+		return []string{"application/json"}
+	}
+
+	return o.ContentTypes
+}
+
+// bodyOptions returns options of route, or api-wide options if route does not override them
+func (api *API) bodyOptions(route *BodyOptions) *BodyOptions {
+	if route != nil {
+		return route
+	}
+
+	return &api.Body
+}
+
+// acceptsContentType reports if content type of request is accepted
+func (o *BodyOptions) acceptsContentType(req *http.Request) bool {
+	if len(o.ContentTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	if err != nil {
+		return false
+	}
+
+	return slices.Contains(o.ContentTypes, mediaType)
+}
+
+func (o *BodyOptions) decode(r io.Reader, value any) error {
+	decoder := json.NewDecoder(r)
+
+	if o.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(value); err != nil {
+		return err
+	}
+
+	if o.SingleValue {
+		if _, err := decoder.Token(); err != io.EOF {
+			return errTrailingData
+		}
+	}
+
+	return nil
+}
+
+// bodyErrorStatus returns status of body decoding error, 413 if body exceeded limit
+func bodyErrorStatus(err error) int {
+	var maxBytesError *http.MaxBytesError
+
+	if errors.As(err, &maxBytesError) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusBadRequest
+}
+
+// bodyErrorDetail returns detail of body decoding error reported to client
+func bodyErrorDetail(err error) string {
+	var maxBytesError *http.MaxBytesError
+
+	if errors.As(err, &maxBytesError) {
+		return fmt.Sprintf("request body too large, limit is %d bytes", maxBytesError.Limit)
+	}
+
+	return fmt.Sprintf("invalid request body: %v", err)
+}
@@ -0,0 +1,89 @@
+package goapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func postCalculation(api *goapi.API, spec goapi.RouteSpec, contentType string, body string) int {
+	appRouter := api.Router()
+	handle := appRouter.Post("/calculate", Calculate, spec)
+
+	req := httptest.NewRequest("POST", "/calculate", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	recorder := httptest.NewRecorder()
+	handle(recorder, req, httprouter.Params{})
+
+	return recorder.Code
+}
+
+func TestBodyOptions(t *testing.T) {
+	newAPI := func() *goapi.API {
+		api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+		return &api
+	}
+
+	body := `{"left":1,"right":2}`
+
+	api := newAPI()
+	api.Body.MaxBytes = 8
+	assert.Exactly(t, http.StatusRequestEntityTooLarge, postCalculation(api, goapi.RouteSpec{}, "", body))
+
+	api = newAPI()
+	api.Body.MaxBytes = 8
+	route := goapi.RouteSpec{Body: &goapi.BodyOptions{MaxBytes: 1024}}
+	assert.Exactly(t, http.StatusOK, postCalculation(api, route, "", body), "Route options must override api options")
+
+	strict := goapi.RouteSpec{Body: &goapi.BodyOptions{DisallowUnknownFields: true, SingleValue: true}}
+	assert.Exactly(t, http.StatusOK, postCalculation(newAPI(), strict, "", body+"\n"))
+	assert.Exactly(t, http.StatusBadRequest, postCalculation(newAPI(), strict, "", `{"left":1,"middle":2}`))
+	assert.Exactly(t, http.StatusBadRequest, postCalculation(newAPI(), strict, "", body+`{}`))
+	assert.Exactly(t, http.StatusOK, postCalculation(newAPI(), goapi.RouteSpec{}, "", body+`{}`), "Trailing data is accepted by default")
+
+	typed := goapi.RouteSpec{Body: &goapi.BodyOptions{ContentTypes: []string{"application/json"}}}
+	assert.Exactly(t, http.StatusOK, postCalculation(newAPI(), typed, "application/json; charset=utf-8", body))
+	assert.Exactly(t, http.StatusUnsupportedMediaType, postCalculation(newAPI(), typed, "text/plain", body))
+	assert.Exactly(t, http.StatusUnsupportedMediaType, postCalculation(newAPI(), typed, "", body))
+}
+
+func TransferPage(transfer Transfer, page Page) (Result, goapi.APIError) {
+	return Result{Result: transfer.Amount}, nil
+}
+
+func TestBodyRequireFields(t *testing.T) {
+	post := func(options *goapi.BodyOptions, path string, body string) *httptest.ResponseRecorder {
+		api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+		appRouter := api.Router()
+		appRouter.Post("/transfers", MakeTransfer, goapi.RouteSpec{Body: options})
+		appRouter.Post("/pages", TransferPage, goapi.RouteSpec{Body: options})
+
+		recorder := httptest.NewRecorder()
+		api.Handler().ServeHTTP(recorder, httptest.NewRequest("POST", path, strings.NewReader(body)))
+		return recorder
+	}
+
+	required := &goapi.BodyOptions{RequireFields: true}
+
+	assert.Exactly(t, http.StatusOK, post(nil, "/transfers", `{"note": "gift"}`).Code, "Required fields are documented only by default")
+
+	recorder := post(required, "/transfers", `{"note": "gift"}`)
+	assert.Exactly(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "missing required field (amount)")
+
+	assert.Exactly(t, http.StatusOK, post(required, "/transfers", `{"amount": 0}`).Code, "Present zero value of required field rejected")
+
+	recorder = post(required, "/pages", `{"transfer": {"note": "gift"}, "page": {}}`)
+	assert.Exactly(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "transfer: missing required field (amount)")
+
+	assert.Exactly(t, http.StatusOK, post(required, "/pages", `{"transfer": {"amount": 5}}`).Code)
+}
@@ -0,0 +1,360 @@
+package goapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// GoClientOptions configures generated go client
+type GoClientOptions struct {
+	// Package is name of generated package, defaults to client
+	Package string
+}
+
+type clientImport struct {
+	Alias string
+	Path  string
+}
+
+type clientParam struct {
+	Arg     string
+	Name    string
+	In      ParamIn
+	Style   ParamStyle
+	Explode bool
+	Type    string
+}
+
+type clientBody struct {
+	Arg    string
+	Prefix string
+	Type   string
+}
+
+type clientOperation struct {
+	Name         string
+	Method       string
+	Path         string
+	Summary      string
+	Params       []clientParam
+	Bodies       []clientBody
+	ResponseType string
+}
+
+type clientData struct {
+	Package    string
+	Title      string
+	Imports    []clientImport
+	ErrorType  string
+	Operations []clientOperation
+}
+
+// identifiers used by generated code, imports and arguments must not shadow them
+var clientReserved = []string{
+	"bytes", "context", "encoding", "json", "fmt", "io", "http", "url", "reflect", "strings",
+	"ctx", "c", "req", "out", "err", "request", "newRequest", "formatValues", "formatValue",
+}
+
+// clientImports assigns aliases to packages of types used by client
+type clientImports struct {
+	aliases map[string]string
+	imports []clientImport
+}
+
+func (ci *clientImports) alias(path string) string {
+	if alias, has := ci.aliases[path]; has {
+		return alias
+	}
+
+	base := goIdentifier(path[strings.LastIndex(path, "/")+1:], false)
+	alias := base
+
+	for n := 1; ci.taken(alias); n++ {
+		alias = base + strconv.Itoa(n)
+	}
+
+	ci.aliases[path] = alias
+	ci.imports = append(ci.imports, clientImport{Alias: alias, Path: path})
+
+	return alias
+}
+
+func (ci *clientImports) taken(alias string) bool {
+	if token.IsKeyword(alias) {
+		return true
+	}
+
+	for _, reserved := range clientReserved {
+		if reserved == alias {
+			return true
+		}
+	}
+
+	for _, el := range ci.imports {
+		if el.Alias == alias {
+			return true
+		}
+	}
+
+	return false
+}
+
+// typeExpr returns go expression of type, qualified by aliases of imports
+func (ci *clientImports) typeExpr(Type reflect.Type) (string, error) {
+	if Type.Name() != "" {
+		switch Type.PkgPath() {
+		case "":
+			return Type.Name(), nil
+		case "main":
+			return "", fmt.Errorf("type (%s) of package main cannot be imported by client", Type)
+		}
+
+		if strings.ContainsAny(Type.Name(), "[]") {
+			return "", fmt.Errorf("generic type (%s) is not supported by client", Type)
+		}
+
+		return ci.alias(Type.PkgPath()) + "." + Type.Name(), nil
+	}
+
+	switch Type.Kind() {
+	case reflect.Pointer, reflect.Slice:
+		elem, err := ci.typeExpr(Type.Elem())
+		if Type.Kind() == reflect.Pointer {
+			return "*" + elem, err
+		}
+		return "[]" + elem, err
+	case reflect.Array:
+		elem, err := ci.typeExpr(Type.Elem())
+		return fmt.Sprintf("[%d]%s", Type.Len(), elem), err
+	case reflect.Map:
+		key, err := ci.typeExpr(Type.Key())
+		if err != nil {
+			return "", err
+		}
+		value, err := ci.typeExpr(Type.Elem())
+		return "map[" + key + "]" + value, err
+	case reflect.Interface:
+		if Type.NumMethod() == 0 {
+			return "any", nil
+		}
+	}
+
+	return "", fmt.Errorf("type (%s) is not supported by client", Type)
+}
+
+var anonymousFunction = regexp.MustCompile(`^Func\d+$`)
+
+// operationName returns name of client method of operation: operationId, function name or method and path
+func operationName(path string, method EndpointMethod) string {
+	if method.OperationId != "" {
+		return goIdentifier(method.OperationId, true)
+	}
+
+	if method.function != "" && !anonymousFunction.MatchString(method.function) {
+		return method.function
+	}
+
+	name := goIdentifier(string(method.Method), true)
+
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			name += "By"
+		}
+		name += goIdentifier(segment, true)
+	}
+
+	return name
+}
+
+// goIdentifier converts name to camel case go identifier, exported if upper is set
+func goIdentifier(name string, upper bool) string {
+	var out strings.Builder
+
+	next := upper
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			next = out.Len() > 0 || upper
+			continue
+		}
+
+		if next {
+			r = unicode.ToUpper(r)
+		} else if out.Len() == 0 {
+			r = unicode.ToLower(r)
+		}
+
+		next = false
+		out.WriteRune(r)
+	}
+
+	identifier := out.String()
+
+	if identifier == "" || unicode.IsDigit([]rune(identifier)[0]) {
+		identifier = "x" + identifier
+		if upper {
+			identifier = "X" + identifier[1:]
+		}
+	}
+
+	return identifier
+}
+
+// uniqueName returns name not contained in used and adds it
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+
+	for n := 2; used[unique]; n++ {
+		unique = name + strconv.Itoa(n)
+	}
+
+	used[unique] = true
+
+	return unique
+}
+
+func newClientData(api *API, opts GoClientOptions) (clientData, error) {
+	imports := &clientImports{aliases: make(map[string]string)}
+
+	data := clientData{
+		Package: opts.Package,
+		Title:   api.Meta.Title,
+	}
+
+	if data.Package == "" {
+		data.Package = "client"
+	}
+
+	if data.Title == "" {
+		data.Title = "api"
+	}
+
+	errorType, err := imports.typeExpr(api.errorOut)
+	if err != nil {
+		return clientData{}, err
+	}
+
+	data.ErrorType = errorType
+	operationNames := map[string]bool{"New": true}
+
+	for _, entry := range api.Endpoints {
+		for _, method := range entry.Methods {
+			operation := clientOperation{
+				Name:    uniqueName(operationName(entry.Path, method), operationNames),
+				Method:  strings.ToUpper(string(method.Method)),
+				Path:    entry.Path,
+				Summary: method.Summary,
+			}
+
+			args := map[string]bool{}
+			for _, reserved := range clientReserved {
+				args[reserved] = true
+			}
+
+			for _, param := range method.Parameters {
+				paramType, err := imports.typeExpr(param.sourceType)
+				if err != nil {
+					return clientData{}, fmt.Errorf("%s %s: %w", method.Method, entry.Path, err)
+				}
+
+				// optional parameters are pointers, so zero values override defaults and nil omits them
+				if !param.Required {
+					switch param.sourceType.Kind() {
+					case reflect.Pointer, reflect.Slice, reflect.Map:
+					default:
+						paramType = "*" + paramType
+					}
+				}
+
+				arg := goIdentifier(param.Name, false)
+				if token.IsKeyword(arg) {
+					arg += "Param"
+				}
+
+				operation.Params = append(operation.Params, clientParam{
+					Arg:     uniqueName(arg, args),
+					Name:    param.Name,
+					In:      param.In,
+					Style:   param.Style,
+					Explode: param.Explode,
+					Type:    paramType,
+				})
+			}
+
+			for _, bodyType := range method.bodyTypes {
+				typeExpr, err := imports.typeExpr(bodyType)
+				if err != nil {
+					return clientData{}, fmt.Errorf("%s %s: %w", method.Method, entry.Path, err)
+				}
+
+				operation.Bodies = append(operation.Bodies, clientBody{
+					Arg:    uniqueName(goIdentifier(bodyType.Name(), false), args),
+					Prefix: schemePrefix(bodyType.Name()),
+					Type:   typeExpr,
+				})
+			}
+
+			if method.ResponseType != "" {
+				for _, schema := range api.Schemas {
+					if schema.Name == method.ResponseType {
+						operation.ResponseType, err = imports.typeExpr(schema.sourceType)
+						break
+					}
+				}
+
+				if err != nil {
+					return clientData{}, fmt.Errorf("%s %s: %w", method.Method, entry.Path, err)
+				}
+			}
+
+			data.Operations = append(data.Operations, operation)
+		}
+	}
+
+	data.Imports = imports.imports
+
+	return data, nil
+}
+
+// GenerateGoClient writes go client package with method per operation. Client takes parameter and body types
+// of endpoints, so they must be declared in importable packages. Optional parameters are taken as pointers.
+func (api *API) GenerateGoClient(w io.Writer, opts GoClientOptions) error {
+	data, err := newClientData(api, opts)
+
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.ParseFS(templateFS, "client.go.tmpl")
+
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	source, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		return fmt.Errorf("invalid client source: %w", err)
+	}
+
+	_, err = w.Write(source)
+
+	return err
+}
@@ -0,0 +1,113 @@
+package goapi_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/masnyjimmy/goapi"
+	"github.com/masnyjimmy/goapi/internal/testapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// goPackage writes files into temporary package of module, so it may import internal packages,
+// and runs go command on it, e.g. build or run. It returns output of command, errors include its stderr.
+func goPackage(t *testing.T, files map[string][]byte, command string) ([]byte, error) {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("compiles generated code")
+	}
+
+	dir, err := os.MkdirTemp(".", "goapi-generated-")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), source, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command("go", command, "./"+filepath.ToSlash(dir))
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+
+	if err != nil {
+		return out, fmt.Errorf("go %s: %w\n%s", command, err, stderr.String())
+	}
+
+	return out, nil
+}
+
+func TestGenerateGoClient(t *testing.T) {
+	api := testapi.New()
+
+	var buf bytes.Buffer
+	assert.NoError(t, api.GenerateGoClient(&buf, goapi.GoClientOptions{Package: "users"}))
+
+	source := buf.String()
+
+	assert.Contains(t, source, "package users")
+	assert.Contains(t, source, `testapi "github.com/masnyjimmy/goapi/internal/testapi"`)
+	assert.Contains(t, source, "Body       goapi.DefaultErrorType")
+	assert.Contains(t, source, "func (c *Client) Calculate(ctx context.Context, calculation testapi.Calculation) (testapi.Result, error)")
+	assert.Contains(t, source, "func (c *Client) FindUser(ctx context.Context, id testapi.UserID, fields testapi.Fields, verbose *testapi.Verbose) (testapi.User, error)")
+	assert.Contains(t, source, "func (c *Client) PutUsersById(ctx context.Context")
+
+	_, err := goPackage(t, map[string][]byte{"client.go": buf.Bytes()}, "build")
+	assert.NoError(t, err)
+}
+
+// clientMain calls api served by testapi through client generated into package main
+const clientMain = `package main
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+
+	"github.com/masnyjimmy/goapi/internal/testapi"
+)
+
+func main() {
+	server := httptest.NewServer(testapi.New().Handler())
+	defer server.Close()
+
+	client := New(server.URL, nil)
+	limit := testapi.Limit(0)
+
+	for _, limit := range []*testapi.Limit{&limit, nil} {
+		item, err := client.GetItem(context.Background(), 7, 3, limit)
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("%+v\n", item)
+	}
+}
+`
+
+func TestGoClientCalls(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, testapi.New().GenerateGoClient(&buf, goapi.GoClientOptions{Package: "main"}))
+
+	output, err := goPackage(t, map[string][]byte{"client.go": buf.Bytes(), "main.go": []byte(clientMain)}, "run")
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "{UserID:7 Index:3 Limit:0}\n{UserID:7 Index:3 Limit:20}\n", string(output), "Zero value of optional parameter not sent")
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/masnyjimmy/goapi"
+)
+
+func diffCommand(args []string) error {
+	if len(args) != 2 {
+		return errors.New("diff takes old and new spec files")
+	}
+
+	documents := make([]*goapi.SpecDocument, 2)
+
+	for i, name := range args {
+		data, err := os.ReadFile(name)
+
+		if err != nil {
+			return err
+		}
+
+		documents[i], err = goapi.ParseSpec(data)
+
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", name, err)
+		}
+	}
+
+	diff := goapi.DiffSpecs(documents[0], documents[1])
+
+	fmt.Fprint(os.Stdout, diff.Report())
+
+	// only changes breaking existing clients fail
+	if diff.HasBreaking() {
+		return errFailed
+	}
+
+	return nil
+}
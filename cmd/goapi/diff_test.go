@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const oldSpec = `
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      summary: List
+      responses:
+        '200':
+          description: ok
+`
+
+func writeSpecFile(t *testing.T, content string) string {
+	name := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o644))
+	return name
+}
+
+func TestDiffCommand(t *testing.T) {
+	old := writeSpecFile(t, oldSpec)
+
+	t.Run("non-breaking", func(t *testing.T) {
+		summary := writeSpecFile(t, oldSpec+`
+  /teams:
+    get:
+      responses:
+        '200':
+          description: ok
+`)
+		assert.NoError(t, diffCommand([]string{old, summary}))
+	})
+
+	t.Run("breaking", func(t *testing.T) {
+		removed := writeSpecFile(t, "paths: {}\n")
+		assert.ErrorIs(t, diffCommand([]string{old, removed}), errFailed)
+	})
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// harness calls registration function of user package and runs mode on returned api
+var harnessTemplate = template.Must(template.New("harness").Parse(`// Code generated by goapi. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/masnyjimmy/goapi"
+	target "{{.Import}}"
+)
+
+func main() {
+	var api *goapi.API = target.{{.Func}}()
+
+	if err := run(api, os.Args[1], os.Args[2], os.Args[3:]); err != nil {
+		fmt.Fprintln(os.Stderr, "goapi:", err)
+		os.Exit(1)
+	}
+}
+
+func run(api *goapi.API, mode string, out string, args []string) error {
+	switch mode {
+	case "ts":
+		if out == "" {
+			out = "."
+		}
+		return api.GenerateTypeScript(out)
+	case "lint":
+		if err := api.Validate(); err != nil {
+			return err
+		}
+		issues := api.Lint()
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		if len(issues) > 0 {
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	w := os.Stdout
+
+	if out != "" {
+		file, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if mode == "client" {
+		return api.GenerateGoClient(w, goapi.GoClientOptions{Package: args[0]})
+	}
+
+	return api.WriteSpec(w)
+}
+`))
+
+// runHarness writes harness into temporary directory of current module and runs it with go run
+func runHarness(pkg string, fn string, args []string) error {
+	list := exec.Command("go", "list", "-f", "{{.ImportPath}}", pkg)
+	list.Stderr = os.Stderr
+
+	importPath, err := list.Output()
+
+	if err != nil {
+		return fmt.Errorf("resolve package (%s): %w", pkg, err)
+	}
+
+	dir, err := os.MkdirTemp(".", "goapi-harness-")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(dir)
+
+	file, err := os.Create(filepath.Join(dir, "main.go"))
+
+	if err != nil {
+		return err
+	}
+
+	err = harnessTemplate.Execute(file, map[string]string{
+		"Import": strings.TrimSpace(string(importPath)),
+		"Func":   fn,
+	})
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", append([]string{"run", "./" + filepath.ToSlash(dir)}, args...)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return errFailed
+		}
+		return err
+	}
+
+	return nil
+}
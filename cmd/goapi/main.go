@@ -0,0 +1,110 @@
+// Command goapi exports, lints and compares OpenAPI documents of goapi services, and generates code of them.
+//
+//	goapi spec -pkg ./api -func NewAPI [-o openapi.yaml]
+//	goapi gen client -pkg ./api -func NewAPI [-package client] [-o client.go]
+//	goapi gen ts -pkg ./api -func NewAPI [-o dir]
+//	goapi gen server -spec openapi.yaml [-package api] [-func RegisterRoutes] [-o server.go]
+//	goapi lint -pkg ./api -func NewAPI
+//	goapi diff old.yaml new.yaml
+//	goapi mock [-addr localhost:8080] [-overrides overrides.json] [-seed n] openapi.yaml
+//
+// Commands taking -pkg build and run a harness calling func, which must have signature
+// func() *goapi.API, inside module of current directory. Nothing is downloaded.
+// Diff exits with status 1 only when new document breaks clients of old one.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+const usage = `usage:
+  goapi spec -pkg <package> -func <name> [-o file]
+  goapi gen client -pkg <package> -func <name> [-package name] [-o file]
+  goapi gen ts -pkg <package> -func <name> [-o dir]
+  goapi gen server -spec <file> [-package name] [-func name] [-o file]
+  goapi lint -pkg <package> -func <name>
+  goapi diff <old.yaml> <new.yaml>
+  goapi mock [-addr host:port] [-overrides file] [-seed n] <spec.yaml>
+`
+
+// errFailed reports failure already printed by command
+var errFailed = errors.New("failed")
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if !errors.Is(err, errFailed) {
+			fmt.Fprintln(os.Stderr, "goapi:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return errFailed
+	}
+
+	switch args[0] {
+	case "spec":
+		return harnessCommand("spec", args[1:], nil)
+	case "gen":
+		if len(args) < 2 {
+			break
+		}
+		switch args[1] {
+		case "client":
+			return harnessCommand("client", args[2:], func(flags *flag.FlagSet) {
+				flags.String("package", "client", "name of generated package")
+			})
+		case "ts":
+			return harnessCommand("ts", args[2:], nil)
+		case "server":
+			return serverCommand(args[2:])
+		}
+	case "lint":
+		return harnessCommand("lint", args[1:], nil)
+	case "diff":
+		return diffCommand(args[1:])
+	case "mock":
+		return mockCommand(args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return nil
+	}
+
+	fmt.Fprint(os.Stderr, usage)
+	return errFailed
+}
+
+// harnessCommand parses flags of command and runs it by harness
+func harnessCommand(mode string, args []string, extra func(*flag.FlagSet)) error {
+	flags := flag.NewFlagSet(mode, flag.ContinueOnError)
+
+	pkg := flags.String("pkg", ".", "package declaring func")
+	fn := flags.String("func", "", "function returning *goapi.API")
+	out := flags.String("o", "", "output file, or directory of ts, defaults to stdout or current directory")
+
+	if extra != nil {
+		extra(flags)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return errFailed
+	}
+
+	if *fn == "" {
+		return errors.New("missing -func")
+	}
+
+	harnessArgs := []string{mode, *out}
+
+	if packageName := flags.Lookup("package"); packageName != nil {
+		harnessArgs = append(harnessArgs, packageName.Value.String())
+	}
+
+	return runHarness(*pkg, *fn, harnessArgs)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/masnyjimmy/goapi"
+)
+
+// mockOverride is override of operation in overrides file, delay is duration like 250ms
+type mockOverride struct {
+	Status  int    `json:"status"`
+	Delay   string `json:"delay"`
+	Fixture string `json:"fixture"`
+}
+
+// mockCommand serves mock responses of openapi document
+func mockCommand(args []string) error {
+	flags := flag.NewFlagSet("mock", flag.ContinueOnError)
+
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	overridesFile := flags.String("overrides", "", "json file of overrides by operationId or \"METHOD path\", e.g. \"GET /users/{id}\"")
+	seed := flags.Uint64("seed", 0, "seed of generated responses, random when 0")
+
+	if err := flags.Parse(args); err != nil {
+		return errFailed
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("mock takes spec file")
+	}
+
+	data, err := os.ReadFile(flags.Arg(0))
+
+	if err != nil {
+		return err
+	}
+
+	document, err := goapi.ParseSpec(data)
+
+	if err != nil {
+		return err
+	}
+
+	server := goapi.NewSpecMockServer(document)
+
+	if *seed != 0 {
+		server.Seed(*seed)
+	}
+
+	if *overridesFile != "" {
+		if err := loadOverrides(server, *overridesFile); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "serving mock of %s on http://%s\n", flags.Arg(0), *addr)
+
+	return http.ListenAndServe(*addr, server)
+}
+
+func loadOverrides(server *goapi.MockServer, name string) error {
+	data, err := os.ReadFile(name)
+
+	if err != nil {
+		return err
+	}
+
+	var overrides map[string]mockOverride
+
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+
+	for operation, override := range overrides {
+		var delay time.Duration
+
+		if override.Delay != "" {
+			if delay, err = time.ParseDuration(override.Delay); err != nil {
+				return fmt.Errorf("override of %s: %w", operation, err)
+			}
+		}
+
+		server.Override(operation, goapi.MockOverride{
+			Status:  override.Status,
+			Delay:   delay,
+			Fixture: override.Fixture,
+		})
+	}
+
+	return nil
+}
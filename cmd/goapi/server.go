@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/masnyjimmy/goapi"
+)
+
+// serverCommand generates types, endpoint stubs and registration function of openapi document
+func serverCommand(args []string) error {
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+
+	spec := flags.String("spec", "", "openapi document, yaml or json")
+	out := flags.String("o", "", "output file, defaults to stdout")
+	packageName := flags.String("package", "api", "name of generated package")
+	fn := flags.String("func", "RegisterRoutes", "name of registration function")
+
+	if err := flags.Parse(args); err != nil {
+		return errFailed
+	}
+
+	if *spec == "" {
+		return errors.New("missing -spec")
+	}
+
+	data, err := os.ReadFile(*spec)
+
+	if err != nil {
+		return err
+	}
+
+	document, err := goapi.ParseSpec(data)
+
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+
+	if *out != "" {
+		file, err := os.Create(*out)
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		w = file
+	}
+
+	return goapi.GenerateServer(w, document, goapi.ServerOptions{Package: *packageName, Func: *fn})
+}
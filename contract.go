@@ -0,0 +1,432 @@
+package goapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type ContractMode int
+
+const (
+	// ContractLog logs violations, requests and responses are passed unchanged
+	ContractLog ContractMode = iota
+	// ContractFail rejects violating requests with 400 and replaces violating responses by 500
+	ContractFail
+)
+
+// ContractOptions configures contract enforcement, zero values use defaults
+type ContractOptions struct {
+	Mode ContractMode
+	// Strict reports properties not declared by object schemas, goapi documents structs without
+	// additionalProperties, so responses like hard-coded error bodies would otherwise pass
+	Strict bool
+	// Logger defaults to slog.Default()
+	Logger *slog.Logger
+}
+
+type contract struct {
+	options  ContractOptions
+	once     sync.Once
+	document *SpecDocument
+}
+
+// Contract validates requests and responses of routes against generated document. It buffers responses,
+// so it is meant for tests and staging.
+func (api *API) Contract(opts ContractOptions) {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+
+	api.contract = &contract{options: opts}
+}
+
+// specDocument returns document of api, built on first request when all routes are registered
+func (c *contract) specDocument(api *API) *SpecDocument {
+	c.once.Do(func() {
+		document, err := api.SpecDocument()
+
+		if err != nil {
+			c.options.Logger.Error("contract disabled, invalid spec", slog.String("error", err.Error()))
+			return
+		}
+
+		c.document = document
+	})
+
+	return c.document
+}
+
+// responseBuffer holds response of handler until it is validated
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+func (b *responseBuffer) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *responseBuffer) Write(data []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(data)
+}
+
+func (b *responseBuffer) writeTo(w http.ResponseWriter) {
+	for key, values := range b.header {
+		w.Header()[key] = values
+	}
+
+	w.WriteHeader(b.status)
+	w.Write(b.body.Bytes())
+}
+
+// withContract validates requests and responses of route, resolved per request so it may be enabled after routes
+func withContract(api *API, path string, endpointMethod *EndpointMethod, handle httprouter.Handle) httprouter.Handle {
+	method := string(endpointMethod.Method)
+
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		c := api.contract
+
+		if c == nil {
+			handle(w, req, params)
+			return
+		}
+
+		document := c.specDocument(api)
+
+		var operation *SpecOperation
+		if document != nil {
+			operation = document.Paths[path][method]
+		}
+
+		if operation == nil {
+			handle(w, req, params)
+			return
+		}
+
+		log := func(side string, status int, violations []string) {
+			c.options.Logger.Warn("contract violation",
+				slog.String("method", strings.ToUpper(method)),
+				slog.String("route", path),
+				slog.String("operationId", operation.OperationId),
+				slog.String("side", side),
+				slog.Int("status", status),
+				slog.Any("violations", violations),
+			)
+		}
+
+		// body limit of route applies before request body is buffered for validation
+		if options := api.bodyOptions(endpointMethod.BodyOptions); options.MaxBytes > 0 && req.Body != nil {
+			req.Body = http.MaxBytesReader(w, req.Body, options.MaxBytes)
+		}
+
+		violations, err := document.validateRequest(operation, req, params, c.options.Strict)
+
+		if err != nil {
+			invalidRequest(w, req, api.errorHandler, bodyErrorStatus(err), "%s", bodyErrorDetail(err))
+			return
+		}
+
+		if len(violations) > 0 {
+			log("request", 0, violations)
+
+			if c.options.Mode == ContractFail {
+				invalidRequest(w, req, api.errorHandler, http.StatusBadRequest, "request violates contract: %s", strings.Join(violations, "; "))
+				return
+			}
+		}
+
+		buffer := &responseBuffer{header: make(http.Header)}
+		handle(buffer, req, params)
+
+		if buffer.status == 0 {
+			buffer.status = http.StatusOK
+		}
+
+		if violations := document.validateResponse(operation, buffer.status, buffer.body.Bytes(), c.options.Strict); len(violations) > 0 {
+			log("response", buffer.status, violations)
+
+			if c.options.Mode == ContractFail {
+				invalidRequest(w, req, api.errorHandler, http.StatusInternalServerError, "response violates contract: %s", strings.Join(violations, "; "))
+				return
+			}
+		}
+
+		buffer.writeTo(w)
+	}
+}
+
+// validateRequest returns violations of parameters and body of request, strict reports undeclared properties.
+// Error is returned if body can not be read, e.g. it exceeds limit.
+func (d *SpecDocument) validateRequest(operation *SpecOperation, req *http.Request, params httprouter.Params, strict bool) ([]string, error) {
+	var violations []string
+	query := req.URL.Query()
+
+	for _, param := range operation.Parameters {
+		location := "parameter " + param.Name
+		style, explode := param.serialization()
+
+		var values []string
+
+		switch param.In {
+		case string(ParamPath):
+			if value := params.ByName(param.Name); value != "" {
+				values = []string{value}
+			}
+		case string(ParamQuery):
+			if style == string(StyleDeepObject) {
+				for key := range query {
+					if strings.HasPrefix(key, param.Name+"[") {
+						values = append(values, key)
+					}
+				}
+				if len(values) == 0 && param.Required {
+					violations = append(violations, location+": missing required parameter")
+				}
+				continue
+			}
+			values = query[param.Name]
+		case string(ParamHeader):
+			values = req.Header.Values(param.Name)
+		case string(ParamCookie):
+			if cookie, err := req.Cookie(param.Name); err == nil {
+				values = []string{cookie.Value}
+			}
+		}
+
+		if len(values) == 0 {
+			if param.Required {
+				violations = append(violations, location+": missing required parameter")
+			}
+			continue
+		}
+
+		schema := d.resolve(param.Schema)
+
+		if schema == nil {
+			continue
+		}
+
+		var value any
+
+		if schemaType(schema) == JsonArray {
+			items := d.resolve(schema.Items)
+			parts := splitValues(values, ParamStyle(style), explode)
+			array := make([]any, 0, len(parts))
+
+			for _, part := range parts {
+				array = append(array, rawParamValue(part, items))
+			}
+
+			value = array
+		} else {
+			value = rawParamValue(values[0], schema)
+		}
+
+		violations = d.validateValue(schema, value, location, false, violations)
+	}
+
+	if operation.RequestBody == nil {
+		return violations, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		if operation.RequestBody.Required {
+			violations = append(violations, "request body: missing required body")
+		}
+		return violations, nil
+	}
+
+	return d.validateJSON(jsonSchemaOf(operation.RequestBody.Content), data, "request body", strict, violations), nil
+}
+
+// ValidateResponse returns violations of response of operation against document, nil if operation is not documented
+func (d *SpecDocument) ValidateResponse(path, method string, status int, body []byte) []string {
+	operation := d.Paths[path][strings.ToLower(method)]
+
+	if operation == nil {
+		return nil
+	}
+
+	return d.validateResponse(operation, status, body, false)
+}
+
+// validateResponse returns violations of response body and status, which must be documented
+func (d *SpecDocument) validateResponse(operation *SpecOperation, status int, body []byte, strict bool) []string {
+	location := "response " + strconv.Itoa(status)
+	response := matchResponse(operation, status)
+
+	if response == nil {
+		return []string{location + ": undocumented status"}
+	}
+
+	schema := jsonSchemaOf(response.Content)
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if schema != nil && status != http.StatusNoContent && status != http.StatusNotModified {
+			return []string{location + ": missing documented body"}
+		}
+		return nil
+	}
+
+	if schema == nil {
+		return []string{location + ": undocumented body"}
+	}
+
+	return d.validateJSON(schema, body, location, strict, nil)
+}
+
+func (d *SpecDocument) validateJSON(schema *SpecSchema, data []byte, location string, strict bool, violations []string) []string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+
+	if err := decoder.Decode(&value); err != nil {
+		return append(violations, fmt.Sprintf("%s: invalid json: %v", location, err))
+	}
+
+	return d.validateValue(schema, value, location, strict, violations)
+}
+
+// rawParamValue converts raw parameter value to json value of schema type, invalid values stay strings
+func rawParamValue(raw string, schema *SpecSchema) any {
+	if schema == nil {
+		return raw
+	}
+
+	switch schemaType(schema) {
+	case JsonInteger, JsonNumber:
+		if _, err := strconv.ParseFloat(raw, 64); err == nil {
+			return json.Number(raw)
+		}
+	case JsonBoolean:
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+	}
+
+	return raw
+}
+
+// jsonTypeOf returns json type of value decoded with UseNumber
+func jsonTypeOf(value any) JsonType {
+	switch value := value.(type) {
+	case nil:
+		return JsonNull
+	case bool:
+		return JsonBoolean
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return JsonInteger
+		}
+		return JsonNumber
+	case string:
+		return JsonString
+	case []any:
+		return JsonArray
+	}
+
+	return JsonObject
+}
+
+// validateValue appends violations of json value against schema, location prefixes messages.
+// Strict reports properties not declared by schema with properties and without additionalProperties.
+func (d *SpecDocument) validateValue(schema *SpecSchema, value any, location string, strict bool, violations []string) []string {
+	schema = d.resolve(schema)
+
+	if schema == nil {
+		return violations
+	}
+
+	if len(schema.OneOf) > 0 {
+		for _, option := range schema.OneOf {
+			if len(d.validateValue(option, value, location, strict, nil)) == 0 {
+				return violations
+			}
+		}
+		return append(violations, fmt.Sprintf("%s: value matches none of schemas", location))
+	}
+
+	valueType := jsonTypeOf(value)
+
+	if len(schema.Type) > 0 && !slices.Contains(schema.Type, string(valueType)) &&
+		!(valueType == JsonInteger && slices.Contains(schema.Type, string(JsonNumber))) {
+		return append(violations, fmt.Sprintf("%s: expected %s, got %s", location, schema.Type, valueType))
+	}
+
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(member any) bool { return jsonEqual(member, value) }) {
+		violations = append(violations, fmt.Sprintf("%s: value %v is not one of %v", location, value, schema.Enum))
+	}
+
+	if text, ok := value.(string); ok && schema.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, text); err != nil {
+			violations = append(violations, fmt.Sprintf("%s: invalid date-time", location))
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for _, name := range schema.Required {
+			if _, has := value[name]; !has {
+				violations = append(violations, fmt.Sprintf("%s .%s: missing required field", location, name))
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(value)) {
+			if property, has := schema.Properties[name]; has {
+				violations = d.validateValue(property, value[name], location+" ."+name, strict, violations)
+			} else if schema.AdditionalProperties != nil {
+				violations = d.validateValue(schema.AdditionalProperties, value[name], location+" ."+name, strict, violations)
+			} else if strict && len(schema.Properties) > 0 {
+				violations = append(violations, fmt.Sprintf("%s .%s: undeclared property", location, name))
+			}
+		}
+	case []any:
+		for index, item := range value {
+			violations = d.validateValue(schema.Items, item, fmt.Sprintf("%s[%d]", location, index), strict, violations)
+		}
+	}
+
+	return violations
+}
+
+// jsonEqual reports if values encode to the same json
+func jsonEqual(a, b any) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	right, err := json.Marshal(b)
+
+	return err == nil && bytes.Equal(left, right)
+}
@@ -0,0 +1,151 @@
+package goapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type Transfer struct {
+	Amount int    `json:"amount" required:"true"`
+	Note   string `json:"note"`
+}
+
+func MakeTransfer(r goapi.Response, transfer Transfer) (Result, goapi.APIError) {
+	// teapot is not documented by spec
+	if transfer.Note == "teapot" {
+		r.Status = http.StatusTeapot
+	}
+
+	return Result{Result: transfer.Amount}, nil
+}
+
+func newContractAPI(mode goapi.ContractMode, logs *bytes.Buffer) http.Handler {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	appRouter.Post("/transfers", MakeTransfer, goapi.RouteSpec{OperationId: "makeTransfer"})
+
+	api.Contract(goapi.ContractOptions{Mode: mode, Logger: slog.New(slog.NewJSONHandler(logs, nil))})
+
+	return api.Handler()
+}
+
+func postTransfer(handler http.Handler, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/transfers", strings.NewReader(body)))
+	return recorder
+}
+
+func TestContractLog(t *testing.T) {
+	var logs bytes.Buffer
+	handler := newContractAPI(goapi.ContractLog, &logs)
+
+	recorder := postTransfer(handler, `{"amount": 10}`)
+	assert.Exactly(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, logs.String())
+
+	recorder = postTransfer(handler, `{"note": "teapot"}`)
+	assert.Exactly(t, http.StatusTeapot, recorder.Code, "Response changed in log mode")
+
+	var records []map[string]any
+	for line := range strings.Lines(logs.String()) {
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "request", records[0]["side"])
+		assert.Equal(t, []any{"request body .amount: missing required field"}, records[0]["violations"])
+		assert.Equal(t, "response", records[1]["side"])
+		assert.Equal(t, []any{"response 418: undocumented status"}, records[1]["violations"])
+		assert.Equal(t, "makeTransfer", records[1]["operationId"])
+	}
+}
+
+func TestContractFail(t *testing.T) {
+	var logs bytes.Buffer
+	handler := newContractAPI(goapi.ContractFail, &logs)
+
+	recorder := postTransfer(handler, `{"amount": "ten"}`)
+	assert.Exactly(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "request body .amount: expected integer, got string")
+
+	recorder = postTransfer(handler, `{"amount": 1, "note": "teapot"}`)
+	assert.Exactly(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "response violates contract")
+
+	recorder = postTransfer(handler, `{"amount": 1}`)
+	assert.Exactly(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"result": 1}`, recorder.Body.String())
+}
+
+func TestContractBodyLimit(t *testing.T) {
+	for _, mode := range []goapi.ContractMode{goapi.ContractLog, goapi.ContractFail} {
+		var logs bytes.Buffer
+
+		api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+		api.Body.MaxBytes = 16
+		appRouter := api.Router()
+		appRouter.Post("/transfers", MakeTransfer, goapi.RouteSpec{})
+		api.Contract(goapi.ContractOptions{Mode: mode, Logger: slog.New(slog.NewJSONHandler(&logs, nil))})
+
+		recorder := postTransfer(api.Handler(), `{"amount": "ten", "note": "`+strings.Repeat("x", 64)+`"}`)
+
+		assert.Exactly(t, http.StatusRequestEntityTooLarge, recorder.Code, "Body limit bypassed by contract")
+		assert.Contains(t, recorder.Body.String(), "request body too large, limit is 16 bytes")
+		assert.Empty(t, logs.String(), "Oversized body reported as contract violation")
+
+		recorder = postTransfer(api.Handler(), `{"amount": 10}`)
+
+		assert.Exactly(t, http.StatusOK, recorder.Code)
+	}
+}
+
+type FindOrderInput struct {
+	ID int64 `path:"id"`
+}
+
+func FindOrder(ctx context.Context, in FindOrderInput) (Result, error) {
+	// hard-coded error bypasses error type of api
+	return Result{}, goapi.NewAPIError(http.StatusNotFound, "no order", nil)
+}
+
+func TestContractStrict(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		var logs bytes.Buffer
+
+		api := goapi.NewAPI(httprouter.New(), func(r goapi.Response, req *http.Request, err *ShopError) ShopErrorBody {
+			r.Status = http.StatusNotFound
+			return ShopErrorBody{Code: err.Code}
+		}, goapi.AppMeta{})
+		appRouter := api.Router()
+		goapi.Get(&appRouter, "/orders/:id", FindOrder, goapi.RouteSpec{})
+		assert.NoError(t, api.Validate())
+
+		api.Contract(goapi.ContractOptions{Strict: strict, Logger: slog.New(slog.NewJSONHandler(&logs, nil))})
+
+		recorder := httptest.NewRecorder()
+		api.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/orders/7", nil))
+
+		assert.Exactly(t, http.StatusNotFound, recorder.Code)
+		assert.JSONEq(t, `{"detail": "no order"}`, recorder.Body.String())
+
+		if !strict {
+			assert.Empty(t, logs.String(), "Body of other schema without required fields passes")
+			continue
+		}
+
+		assert.Contains(t, logs.String(), `"side":"response"`)
+		assert.Contains(t, logs.String(), "response 404 .detail: undeclared property")
+	}
+}
@@ -0,0 +1,225 @@
+package goapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/cors"
+)
+
+// methods allowed by cors policy when options do not list them
+var defaultCORSMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodOptions,
+}
+
+// corsPolicy is cors configuration of api or router with its handler
+type corsPolicy struct {
+	options cors.Options
+	handler *cors.Cors
+}
+
+func newCORSPolicy(options cors.Options) *corsPolicy {
+	if len(options.AllowedMethods) == 0 {
+		options.AllowedMethods = defaultCORSMethods
+	}
+
+	return &corsPolicy{
+		options: options,
+		handler: cors.New(options),
+	}
+}
+
+// CORS enables cross-origin requests for routes not overriding it by Router.CORS,
+// preflight requests are answered for every registered path
+func (api *API) CORS(options cors.Options) {
+	api.cors = newCORSPolicy(options)
+	api.handlePreflight()
+}
+
+// CORS overrides api-wide cors policy for routes registered later by router and its subrouters
+func (r *Router) CORS(options cors.Options) {
+	r.cors = newCORSPolicy(options)
+	r.api.handlePreflight()
+}
+
+// corsPolicy returns policy of route, or api-wide policy
+func (api *API) corsPolicy(route *corsPolicy) *corsPolicy {
+	if route != nil {
+		return route
+	}
+
+	return api.cors
+}
+
+// handlePreflight answers preflight requests of paths without options route,
+// other options requests are passed to previous handler of router
+func (api *API) handlePreflight() {
+	if api.preflight {
+		return
+	}
+
+	api.preflight = true
+	next := api.router.GlobalOPTIONS
+
+	api.router.GlobalOPTIONS = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if method := req.Header.Get("Access-Control-Request-Method"); method != "" {
+			if policy := api.pathCORSPolicy(req.URL.Path, Method(strings.ToLower(method))); policy != nil {
+				policy.handler.HandlerFunc(w, req)
+				return
+			}
+		}
+
+		if next != nil {
+			next.ServeHTTP(w, req)
+		}
+	})
+}
+
+// pathCORSPolicy returns policy of route matching path and method. Methods without route get policy
+// of other routes of path, so policies of routers answer them too, or api-wide policy.
+func (api *API) pathCORSPolicy(path string, method Method) *corsPolicy {
+	var shared *corsPolicy
+
+	for _, entry := range api.Endpoints {
+		if !matchPath(entry.Path, path) {
+			continue
+		}
+
+		for _, el := range entry.Methods {
+			if el.Method == method {
+				return api.corsPolicy(el.cors)
+			}
+
+			if shared == nil {
+				shared = el.cors
+			}
+		}
+	}
+
+	if shared != nil {
+		return shared
+	}
+
+	return api.cors
+}
+
+// withCORS applies cors policy of route, resolved per request so api-wide policy may be set after routes
+func withCORS(api *API, route *corsPolicy, handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		if policy := api.corsPolicy(route); policy != nil {
+			policy.handler.HandlerFunc(w, req)
+
+			// preflight is answered by policy
+			if req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != "" {
+				return
+			}
+		}
+
+		handle(w, req, params)
+	}
+}
+
+// matchPath reports if path matches httprouter path template
+func matchPath(template string, path string) bool {
+	templateParts := strings.Split(template, "/")
+	pathParts := strings.Split(path, "/")
+
+	for index, part := range templateParts {
+		if strings.HasPrefix(part, "*") {
+			return len(pathParts) >= index
+		}
+
+		if index >= len(pathParts) {
+			return false
+		}
+
+		if strings.HasPrefix(part, ":") {
+			if pathParts[index] == "" {
+				return false
+			}
+			continue
+		}
+
+		if part != pathParts[index] {
+			return false
+		}
+	}
+
+	return len(templateParts) == len(pathParts)
+}
+
+// corsHeaders returns access control headers sent with responses of route, if cors is enabled
+func corsHeaders(api *API, method EndpointMethod) []headerSpec {
+	policy := api.corsPolicy(method.cors)
+
+	if policy == nil {
+		return nil
+	}
+
+	headers := []headerSpec{{Name: "Access-Control-Allow-Origin", Type: JsonString}}
+
+	if policy.options.AllowCredentials {
+		headers = append(headers, headerSpec{Name: "Access-Control-Allow-Credentials", Type: JsonString})
+	}
+
+	if len(policy.options.ExposedHeaders) > 0 {
+		headers = append(headers, headerSpec{Name: "Access-Control-Expose-Headers", Type: JsonString})
+	}
+
+	return headers
+}
+
+// corsPreflight describes generated preflight operation of path
+type corsPreflight struct {
+	Status  int
+	Headers []headerSpec
+}
+
+// preflightOf returns preflight operation of path answered by GlobalOPTIONS, nil if path has options route
+// or cors is disabled
+func preflightOf(api *API, entry EndpointEntry) *corsPreflight {
+	var policy *corsPolicy
+
+	for _, method := range entry.Methods {
+		if method.Method == MethodOptions {
+			return nil
+		}
+
+		if policy == nil {
+			policy = api.corsPolicy(method.cors)
+		}
+	}
+
+	if policy == nil {
+		return nil
+	}
+
+	status := policy.options.OptionsSuccessStatus
+	if status == 0 {
+		status = http.StatusNoContent
+	}
+
+	headers := []headerSpec{
+		{Name: "Access-Control-Allow-Origin", Type: JsonString},
+		{Name: "Access-Control-Allow-Methods", Type: JsonString},
+		{Name: "Access-Control-Allow-Headers", Type: JsonString},
+	}
+
+	if policy.options.MaxAge > 0 {
+		headers = append(headers, headerSpec{Name: "Access-Control-Max-Age", Type: JsonInteger})
+	}
+
+	if policy.options.AllowCredentials {
+		headers = append(headers, headerSpec{Name: "Access-Control-Allow-Credentials", Type: JsonString})
+	}
+
+	return &corsPreflight{
+		Status:  status,
+		Headers: headers,
+	}
+}
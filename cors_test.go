@@ -0,0 +1,87 @@
+package goapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/rs/cors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{})
+	appRouter.AddRoute("/internal", func(r *goapi.Router) {
+		r.CORS(cors.Options{AllowedOrigins: []string{"https://admin.example"}})
+		r.Post("/calculate", Calculate, goapi.RouteSpec{})
+	})
+
+	// api-wide policy may be set after routes
+	api.CORS(cors.Options{AllowedOrigins: []string{"https://app.example"}})
+
+	preflight := func(path string, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("OPTIONS", path, nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+
+		recorder := httptest.NewRecorder()
+		api.Handler().ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	recorder := preflight("/calculate", "https://app.example")
+	assert.Exactly(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "https://app.example", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "POST", recorder.Header().Get("Access-Control-Allow-Methods"))
+
+	recorder = preflight("/internal/calculate", "https://app.example")
+	assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"), "Router override not applied")
+
+	recorder = preflight("/internal/calculate", "https://admin.example")
+	assert.Equal(t, "https://admin.example", recorder.Header().Get("Access-Control-Allow-Origin"))
+
+	req := httptest.NewRequest("POST", "/calculate", strings.NewReader(`{"left": 1, "right": 2}`))
+	req.Header.Set("Origin", "https://app.example")
+
+	recorder = httptest.NewRecorder()
+	api.Handler().ServeHTTP(recorder, req)
+
+	assert.Exactly(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "https://app.example", recorder.Header().Get("Access-Control-Allow-Origin"))
+
+	t.Chdir(t.TempDir())
+	assert.NoError(t, api.Setup())
+
+	spec, err := os.ReadFile("openapi.yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(spec), "Access-Control-Allow-Origin:")
+	assert.Contains(t, string(spec), "summary: CORS preflight")
+}
+
+func TestCORSRouterPolicyUnroutedMethod(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	appRouter.AddRoute("/internal", func(r *goapi.Router) {
+		r.CORS(cors.Options{AllowedOrigins: []string{"https://admin.example"}})
+		r.Post("/calculate", Calculate, goapi.RouteSpec{})
+	})
+
+	req := httptest.NewRequest("OPTIONS", "/internal/calculate", nil)
+	req.Header.Set("Origin", "https://admin.example")
+	req.Header.Set("Access-Control-Request-Method", "PUT")
+
+	recorder := httptest.NewRecorder()
+	api.Handler().ServeHTTP(recorder, req)
+
+	assert.Exactly(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "https://admin.example", recorder.Header().Get("Access-Control-Allow-Origin"), "Router policy not applied to method without route")
+}
@@ -0,0 +1,43 @@
+package goapi
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// defaultValues splits raw default into values the same way as delimited request value
+func defaultValues(value string, Type reflect.Type, style ParamStyle) []string {
+	if jt, err := resolveJsonType(Type); err == nil && jt.jsonType == JsonArray {
+		return splitValues([]string{value}, style, false)
+	}
+
+	return []string{value}
+}
+
+// parseDefault validates raw default of type and returns its spec (json) representation
+func parseDefault(value string, Type reflect.Type, style ParamStyle) ([]string, string, error) {
+	values := defaultValues(value, Type, style)
+
+	parsedValue, err := parseTypedValue(values, Type)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	bytes, err := json.Marshal(parsedValue.Interface())
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return values, string(bytes), nil
+}
+
+// fieldDefault returns default declared by tag of field, or by Defaulter of its type
+func fieldDefault(field reflect.StructField) (string, bool) {
+	if tag, has := field.Tag.Lookup("default"); has {
+		return tag, true
+	}
+
+	return extractDefault(field.Type)
+}
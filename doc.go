@@ -0,0 +1,13 @@
+// Package goapi builds http handlers and OpenAPI document from plain go functions.
+//
+// Parameters, schemas and their fields are customized by implementing small interfaces
+// on their types, value or pointer receivers are both honored:
+//
+//   - ParamSpecifier, Locator, Styler and Exploder describe parameters only
+//   - ParamParser parses raw parameter values, encoding.TextUnmarshaler is used otherwise
+//   - Formatter, Enumerator and Defaulter apply to parameters, body schemas and nested fields
+//   - SchemaProvider replaces generated json schema of parameter, body schema or field
+//
+// Struct fields can also declare format, enum and default tags, which take precedence
+// over interfaces of the field type.
+package goapi
@@ -1,6 +1,8 @@
 package goapi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
@@ -23,13 +25,28 @@ const (
 	ParamBody      ParamIn = "body"
 )
 
+type ParamStyle string
+
+const (
+	StyleUndefined      ParamStyle = ""
+	StyleForm           ParamStyle = "form"
+	StyleSimple         ParamStyle = "simple"
+	StyleSpaceDelimited ParamStyle = "spaceDelimited"
+	StylePipeDelimited  ParamStyle = "pipeDelimited"
+	StyleDeepObject     ParamStyle = "deepObject"
+)
+
 type Parameter struct {
 	sourceType  reflect.Type
 	Name        string
 	In          ParamIn
 	Required    bool
 	Description string
+	Style       ParamStyle
+	Explode     bool
 	Meta        Meta
+
+	defaultValues []string
 }
 
 type Parameters []Parameter
@@ -43,8 +60,16 @@ type EndpointMethod struct {
 	sourceType   reflect.Type
 	Parameters   Parameters
 	RequestBody  string
+	BodyOptions  *BodyOptions
 	ResponseType string
+	Responses    []ResponseEntry
 	Handler      httprouter.Handle
+
+	cors      *corsPolicy
+	rateLimit *rateLimitPolicy
+	// function is name of endpoint function, bodyTypes are types of body inputs in order
+	function  string
+	bodyTypes []reflect.Type
 }
 
 type EndpointEntry struct {
@@ -52,23 +77,24 @@ type EndpointEntry struct {
 	Methods []EndpointMethod
 }
 
-func (e *EndpointEntry) Set(value EndpointMethod) {
+func (e *EndpointEntry) Set(value EndpointMethod) error {
 	for _, el := range e.Methods {
 		if el.Method == value.Method {
-			panic("duplicate method")
+			return fmt.Errorf("duplicate method")
 		}
 	}
 
 	e.Methods = append(e.Methods, value)
+
+	return nil
 }
 
 type Endpoints []EndpointEntry
 
-func (e *Endpoints) Set(path string, value EndpointMethod) {
+func (e *Endpoints) Set(path string, value EndpointMethod) error {
 	for i := range *e {
 		if (*e)[i].Path == path {
-			(&(*e)[i]).Set(value)
-			return
+			return (&(*e)[i]).Set(value)
 		}
 	}
 
@@ -76,16 +102,28 @@ func (e *Endpoints) Set(path string, value EndpointMethod) {
 		Path:    path,
 		Methods: []EndpointMethod{value},
 	})
+
+	return nil
 }
 
 func (p *Parameters) RegisterParameter(Type reflect.Type, prefix string) (Parameter, error) {
+	return p.registerParameter(Type, prefix, paramOverride{})
+}
+
+func (p *Parameters) registerParameter(Type reflect.Type, prefix string, override paramOverride) (Parameter, error) {
 
 	// read parameter spec
 	name := Type.Name()
 	description := ""
 	var required bool
 
-	if spec := extractSpec(Type); spec != nil {
+	spec := extractSpec(Type)
+
+	if override.spec != nil {
+		spec = override.spec
+	}
+
+	if spec != nil {
 		name = spec.Name
 		required = spec.Required
 		description = spec.Description
@@ -105,17 +143,85 @@ func (p *Parameters) RegisterParameter(Type reflect.Type, prefix string) (Parame
 		in = inSpec
 	}
 
+	if override.in != ParamUndefined {
+		in = override.in
+	}
+
 	// build meta (openapi::schema) part
-	jt, err := resolveJsonType(Type)
+	var meta Meta
 
-	if err != nil {
-		return Parameter{}, err
+	if Type.Kind() == reflect.Struct && !implementsParser(Type) && extractJSONSchema(Type) == nil {
+		// structs can be only passed as deepObject
+		properties, err := buildProperties(Type)
+
+		if err != nil {
+			return Parameter{}, err
+		}
+
+		meta = BuildTypeMeta(JsonObject, Type)
+		meta.Properties = properties
+	} else {
+		resolved, err := resolveMeta(Type)
+
+		if err != nil {
+			return Parameter{}, err
+		}
+
+		meta = resolved
 	}
 
-	meta := BuildTypeMeta(jt.jsonType, Type)
+	// resolve serialization style, defaults follow openapi spec
+	style := extractStyle(Type)
+	explode, hasExplode := extractExplode(Type)
 
-	if _, has := meta.Rest["format"]; has {
-		meta.Rest["format"] = jt.format
+	switch meta.Type {
+	case JsonArray:
+		if !isFlatMeta(meta) {
+			return Parameter{}, fmt.Errorf("array parameter (%s) must contain scalar values", name)
+		}
+		if style == StyleUndefined {
+			style = StyleForm
+			if in == ParamPath || in == ParamHeader {
+				style = StyleSimple
+			}
+		}
+	case JsonObject:
+		if style == StyleUndefined {
+			style = StyleDeepObject
+		}
+		if style != StyleDeepObject || in != ParamQuery {
+			return Parameter{}, fmt.Errorf("object parameter (%s) must be deepObject query parameter", name)
+		}
+		for _, prop := range meta.Properties {
+			if !isFlatMeta(prop.Meta) {
+				return Parameter{}, fmt.Errorf("object parameter (%s) must contain scalar or array values", name)
+			}
+		}
+		if meta.AdditionalProperties != nil && !isFlatMeta(*meta.AdditionalProperties) {
+			return Parameter{}, fmt.Errorf("object parameter (%s) must contain scalar or array values", name)
+		}
+	}
+
+	if !hasExplode {
+		explode = style == StyleForm || style == StyleDeepObject
+	}
+
+	// default value is parsed as it would be provided by request
+	var defaultValues []string
+
+	if value, has := extractDefault(Type); has {
+		if meta.Type == JsonObject {
+			return Parameter{}, fmt.Errorf("object parameter (%s) cannot have default value", name)
+		}
+
+		values, encoded, err := parseDefault(value, Type, style)
+
+		if err != nil {
+			return Parameter{}, fmt.Errorf("invalid default of parameter (%s): %w", name, err)
+		}
+
+		defaultValues = values
+		meta.Rest["default"] = encoded
 	}
 
 	parameter := Parameter{
@@ -124,7 +230,11 @@ func (p *Parameters) RegisterParameter(Type reflect.Type, prefix string) (Parame
 		In:          in,
 		Required:    required,
 		Description: description,
+		Style:       style,
+		Explode:     explode,
 		Meta:        meta,
+
+		defaultValues: defaultValues,
 	}
 
 	*p = append(*p, parameter)
@@ -132,8 +242,21 @@ func (p *Parameters) RegisterParameter(Type reflect.Type, prefix string) (Parame
 	return parameter, nil
 }
 
+// isFlatMeta reports if meta is scalar or array of scalars
+func isFlatMeta(meta Meta) bool {
+	switch meta.Type {
+	case JsonObject:
+		return false
+	case JsonArray:
+		// items of custom schemas are trusted
+		return meta.Items == nil || (meta.Items.Type != JsonArray && meta.Items.Type != JsonObject)
+	}
+
+	return true
+}
+
 func getFunctionName(fn any) string {
-	if reflect.TypeOf(fn).Kind() != reflect.Func {
+	if t := reflect.TypeOf(fn); t == nil || t.Kind() != reflect.Func || reflect.ValueOf(fn).IsNil() {
 		return ""
 	}
 	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
@@ -148,119 +271,253 @@ func getFunctionName(fn any) string {
 	return string(r)
 }
 
+// paramSlot is an input of endpoint, function argument or field of typed input
+type paramSlot struct {
+	Type     reflect.Type
+	override paramOverride
+}
+
+// paramOverride replaces spec and location of parameter type, set by field tags of typed input
+type paramOverride struct {
+	spec *Spec
+	in   ParamIn
+}
+
 func newEndpointMethod(
 	api *API,
 	method Method,
 	prefix string,
 	endpoint Endpoint,
 	spec RouteSpec,
-) EndpointMethod {
+) (EndpointMethod, error) {
 
-	methodName := getFunctionName(endpoint)
 	methodType := reflect.TypeOf(endpoint)
 
+	if methodType == nil || methodType.Kind() != reflect.Func {
+		return EndpointMethod{}, fmt.Errorf("invalid endpoint (%T), must be function", endpoint)
+	}
+
+	endpointMethod, err := newEndpointSpec(method, methodType, spec)
+
+	if err != nil {
+		return EndpointMethod{}, err
+	}
+	endpointMethod.function = getFunctionName(endpoint)
+
+	slots := make([]paramSlot, 0, methodType.NumIn())
+
+	for p := range methodType.NumIn() {
+		slots = append(slots, paramSlot{Type: methodType.In(p)})
+	}
+
+	params, err := registerInputs(api, getFunctionName(endpoint), prefix, slots, &endpointMethod)
+
+	// handle return type
+	errs := []error{err}
+	invalidReturn := fmt.Errorf("invalid return type, must be (%[1]s) or ([T],%[1]s)", api.errorIn)
+
+	switch methodType.NumOut() {
+	case 1:
+		if methodType.Out(0) != api.errorIn {
+			errs = append(errs, invalidReturn)
+		}
+	case 2:
+		if methodType.Out(1) != api.errorIn {
+			errs = append(errs, invalidReturn)
+		}
+		errs = append(errs, registerOutput(api, methodType.Out(0), &endpointMethod))
+	default:
+		errs = append(errs, invalidReturn)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return EndpointMethod{}, err
+	}
+
 	handleData := HandleData{
 		Endpoint: endpoint,
-		Params:   make([]HandleParam, 0),
+		Params:   params,
+		Body:     spec.Body,
+	}
+
+	// build handle and set endpoint
+	endpointMethod.Handler = makeRouterHandle(api, handleData)
+
+	if err := registerEndpoint(api, prefix, &endpointMethod); err != nil {
+		return EndpointMethod{}, err
 	}
 
-	endpointMethod := EndpointMethod{
+	return endpointMethod, nil
+}
+
+func newEndpointSpec(method Method, sourceType reflect.Type, spec RouteSpec) (EndpointMethod, error) {
+	rateLimit := spec.rateLimit
+	if spec.RateLimit != nil {
+		var err error
+		if rateLimit, err = newRateLimitPolicy(*spec.RateLimit); err != nil {
+			return EndpointMethod{}, err
+		}
+	}
+
+	return EndpointMethod{
 		Method:      method,
 		Tags:        spec.Tags,
 		Summary:     spec.Summary,
 		Description: spec.Description,
 		OperationId: spec.OperationId,
-		sourceType:  methodType,
-	}
+		BodyOptions: spec.Body,
+		Responses:   spec.Responses,
+		sourceType:  sourceType,
+		cors:        spec.cors,
+		rateLimit:   rateLimit,
+	}, nil
+}
 
-	for p := range methodType.NumIn() {
+// registerInputs registers parameters and body schemas of endpoint inputs and builds their binding plan,
+// errors of all inputs are joined
+func registerInputs(
+	api *API,
+	methodName string,
+	prefix string,
+	slots []paramSlot,
+	endpointMethod *EndpointMethod,
+) ([]HandleParam, error) {
+
+	handleParams := make([]HandleParam, 0, len(slots))
+	errs := make([]error, 0)
 
-		ParamType := methodType.In(p)
-		handleParam := HandleParam{
-			Special: false,
+	for index, slot := range slots {
+		handleParam, err := registerInput(api, methodName, prefix, slot, endpointMethod)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("input %d (%s): %w", index, slot.Type, err))
+			continue
 		}
 
-		// process parameters, handle special types, schemas, and parameters
-		switch ParamType {
-		case GetType[Response]():
-			handleParam.Special = true
-		default:
-			{
-				if ParamType.Kind() == reflect.Struct {
-					// its struct so its schema -> body -> required
-					handleParam.In = ParamBody
-					handleParam.Required = true
-					handleParam.JsonType = JsonObject
-
-					schema, err := api.Schemas.RegisterSchema(ParamType)
-
-					if err != nil {
-						panic(err)
-					}
+		handleParams = append(handleParams, handleParam)
+	}
 
-					handleParam.Name = schema.Name
+	return handleParams, errors.Join(errs...)
+}
 
-					if endpointMethod.RequestBody == "" {
-						endpointMethod.RequestBody = schema.Name
-					} else {
-						if endpointMethod.RequestBody != methodName {
-							if err := api.SchemeGroups.addScheme(methodName, endpointMethod.RequestBody); err != nil {
-								panic(err)
-							}
-							endpointMethod.RequestBody = methodName
-						}
-						if err := api.SchemeGroups.addScheme(methodName, schema.Name); err != nil {
-							panic(err)
+func registerInput(
+	api *API,
+	methodName string,
+	prefix string,
+	slot paramSlot,
+	endpointMethod *EndpointMethod,
+) (HandleParam, error) {
+
+	ParamType := slot.Type
+	handleParam := HandleParam{
+		Special:   false,
+		paramType: ParamType,
+	}
+
+	// process parameters, handle special types, schemas, and parameters
+	switch ParamType {
+	case GetType[Response](), GetType[context.Context]():
+		handleParam.Special = true
+	default:
+		{
+			if ParamType.Kind() == reflect.Struct && extractStyle(ParamType) != StyleDeepObject && !implementsParser(ParamType) {
+				// its struct so its schema -> body -> required
+				handleParam.In = ParamBody
+				handleParam.Required = true
+				handleParam.JsonType = JsonObject
+
+				schema, err := api.Schemas.RegisterSchema(ParamType)
+
+				if err != nil {
+					return HandleParam{}, err
+				}
+
+				handleParam.Name = schema.Name
+
+				plan, err := compileSchemaPlan(ParamType)
+
+				if err != nil {
+					return HandleParam{}, err
+				}
+
+				handleParam.body = &plan
+				endpointMethod.bodyTypes = append(endpointMethod.bodyTypes, ParamType)
+
+				if endpointMethod.RequestBody == "" {
+					endpointMethod.RequestBody = schema.Name
+				} else {
+					if endpointMethod.RequestBody != methodName {
+						if err := api.SchemeGroups.addScheme(methodName, endpointMethod.RequestBody); err != nil {
+							return HandleParam{}, err
 						}
+						endpointMethod.RequestBody = methodName
 					}
-				} else {
-					parameter, err := endpointMethod.Parameters.RegisterParameter(ParamType, prefix)
-					if err != nil {
-						panic(err)
+					if err := api.SchemeGroups.addScheme(methodName, schema.Name); err != nil {
+						return HandleParam{}, err
 					}
-					handleParam.In = parameter.In
-					handleParam.Required = parameter.Required
-					handleParam.JsonType = parameter.Meta.Type
-					handleParam.Name = parameter.Name
+				}
+			} else {
+				parameter, err := endpointMethod.Parameters.registerParameter(ParamType, prefix, slot.override)
+				if err != nil {
+					return HandleParam{}, err
+				}
+				handleParam.In = parameter.In
+				handleParam.Required = parameter.Required
+				handleParam.JsonType = parameter.Meta.Type
+				handleParam.Name = parameter.Name
+				handleParam.Style = parameter.Style
+				handleParam.Explode = parameter.Explode
+				handleParam.Default = parameter.defaultValues
+
+				if parameter.Style == StyleDeepObject {
+					handleParam.deepObject, err = compileDeepObjectParser(ParamType)
+				} else {
+					handleParam.parse, err = compileParser(ParamType)
+				}
+
+				if err != nil {
+					return HandleParam{}, err
 				}
 			}
 		}
+	}
 
-		handleData.Params = append(handleData.Params, handleParam)
+	return handleParam, nil
+}
+
+func registerOutput(api *API, Type reflect.Type, endpointMethod *EndpointMethod) error {
+	schema, err := api.Schemas.RegisterSchema(Type)
 
+	if err != nil {
+		return fmt.Errorf("invalid response type (%s): %w", Type, err)
 	}
 
-	// handle return type
+	endpointMethod.ResponseType = schema.Name
 
-	switch methodType.NumOut() {
-	case 1:
-		if methodType.Out(0) != api.errorIn {
-			panic(fmt.Errorf("invalid return type, must be (%[1]s) or ([T],%[1]s)", api.errorIn.Name()))
-		}
-	case 2:
-		if methodType.Out(1) != api.errorIn {
-			panic(fmt.Errorf("invalid return type, must be (%[1]s) or ([T],%[1]s)", api.errorIn.Name()))
-		}
-		if schema, err := api.Schemas.RegisterSchema(methodType.Out(0)); err != nil {
-			panic(err)
-		} else {
-			endpointMethod.ResponseType = schema.Name
-		}
-	default:
-		panic(fmt.Errorf("invalid return type, must be (%[1]s) or ([T],%[1]s)", api.errorIn.Name()))
+	return nil
+}
+
+func registerEndpoint(api *API, prefix string, endpointMethod *EndpointMethod) error {
+	endpointMethod.Handler = withContract(api, prefix, endpointMethod, endpointMethod.Handler)
+	endpointMethod.Handler = withRateLimit(api, endpointMethod.rateLimit, endpointMethod.Handler)
+	endpointMethod.Handler = withCORS(api, endpointMethod.cors, endpointMethod.Handler)
+	endpointMethod.Handler = withAccessLog(api, prefix, endpointMethod, endpointMethod.Handler)
+	endpointMethod.Handler = withMetrics(api, prefix, endpointMethod, endpointMethod.Handler)
+	endpointMethod.Handler = withTracing(api, prefix, endpointMethod, endpointMethod.Handler)
+
+	// routes rejected by router, e.g. conflicting paths, are not documented
+	if err := handleRoute(api.router, strings.ToUpper(string(endpointMethod.Method)), prefix, endpointMethod.Handler); err != nil {
+		return err
+	}
+
+	if err := api.Endpoints.Set(prefix, *endpointMethod); err != nil {
+		return err
 	}
 
 	// add tags to api
-	for _, tag := range spec.Tags {
+	for _, tag := range endpointMethod.Tags {
 		api.Tags.Set(tag)
 	}
 
-	// build handle and set endpoint
-	endpointMethod.Handler = makeRouterHandle(api, handleData)
-	api.Endpoints.Set(prefix, endpointMethod)
-
-	// add route
-	api.router.Handle(strings.ToUpper(string(method)), prefix, endpointMethod.Handler)
-
-	return endpointMethod
+	return nil
 }
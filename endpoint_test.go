@@ -103,3 +103,57 @@ func EndpointFail(t *testing.T) {
 
 	assert.Exactly(t, 21, result.Detail, "Invalid calculate result")
 }
+
+type Page struct {
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit" default:"20"`
+	Order  string `json:"order" default:"asc"`
+}
+
+func Paginate(page Page) (Page, goapi.APIError) {
+	return page, nil
+}
+
+func TestBodyFieldDefaults(t *testing.T) {
+	router := httprouter.New()
+	api := goapi.NewAPI(router, goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	handle := appRouter.Route(goapi.MethodPost, "/paginate", Paginate, goapi.RouteSpec{})
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("post", "/paginate", bytes.NewReader([]byte(`{"offset":40,"order":"desc"}`)))
+	handle(recorder, req, httprouter.Params{})
+
+	var page Page
+
+	err := json.Unmarshal(recorder.Body.Bytes(), &page)
+
+	assert.NoError(t, err, "Unable to unmarshal page")
+
+	assert.Exactly(t, Page{Offset: 40, Limit: 20, Order: "desc"}, page, "Defaults not applied")
+}
+
+type BrokenPage struct {
+	Limit int `json:"limit" default:"twenty"`
+}
+
+func BrokenPaginate(page BrokenPage) (Page, goapi.APIError) {
+	return Page{Limit: page.Limit}, nil
+}
+
+func TestBodyFieldInvalidDefault(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	handle := appRouter.Post("/paginate", BrokenPaginate, goapi.RouteSpec{})
+
+	var registrationError *goapi.RegistrationError
+
+	if assert.ErrorAs(t, api.Validate(), &registrationError, "Invalid default not detected at registration") {
+		assert.Contains(t, registrationError.Error(), "invalid default of field (Limit)")
+	}
+
+	recorder := httptest.NewRecorder()
+	handle(recorder, httptest.NewRequest("POST", "/paginate", bytes.NewReader([]byte(`{}`))), httprouter.Params{})
+
+	assert.Exactly(t, http.StatusInternalServerError, recorder.Code)
+}
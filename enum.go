@@ -0,0 +1,102 @@
+package goapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// enumOf returns json encoded values of enum declared by type, nil if type is not an enum
+func enumOf(Type reflect.Type) ([]string, error) {
+	values := extractEnum(Type)
+
+	if values == nil {
+		return nil, nil
+	}
+
+	out := make([]string, 0, len(values))
+
+	for _, value := range values {
+		bytes, err := json.Marshal(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, string(bytes))
+	}
+
+	return out, nil
+}
+
+// fieldEnum returns enum of field declared by enum tag or by type of the field (or its items)
+func fieldEnum(field reflect.StructField) ([]string, error) {
+	itemType := field.Type
+
+	if jt, err := resolveJsonType(itemType); err == nil && jt.jsonType == JsonArray {
+		itemType = derefType(itemType).Elem()
+	}
+
+	tag, has := field.Tag.Lookup("enum")
+
+	if !has {
+		return enumOf(itemType)
+	}
+
+	out := []string{}
+
+	for value := range strings.SplitSeq(tag, ",") {
+		parsedValue, err := parseTypedValue([]string{strings.TrimSpace(value)}, itemType)
+
+		if err != nil {
+			return nil, err
+		}
+
+		bytes, err := json.Marshal(parsedValue.Interface())
+
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, string(bytes))
+	}
+
+	return out, nil
+}
+
+func formatEnum(enum []string) string {
+	return "[" + strings.Join(enum, ", ") + "]"
+}
+
+// checkEnum returns error if value, or any of its items, is not a member of enum
+func checkEnum(value reflect.Value, enum []string) error {
+	if len(enum) == 0 {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() != reflect.Uint8 {
+			for i := 0; i < value.Len(); i++ {
+				if err := checkEnum(value.Index(i), enum); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	bytes, err := json.Marshal(value.Interface())
+
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(enum, string(bytes)) {
+		return fmt.Errorf("value %s is not one of %s", bytes, formatEnum(enum))
+	}
+
+	return nil
+}
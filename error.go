@@ -2,6 +2,7 @@ package goapi
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"reflect"
 )
@@ -26,7 +27,8 @@ func (err *_APIError) Error() string {
 }
 
 type DefaultErrorType struct {
-	Detail string `json:"detail"`
+	Detail    string `json:"detail"`
+	RequestID string `json:"requestId,omitempty"`
 }
 
 func DefaultErrorHandler() ErrorHandler[APIError, DefaultErrorType] {
@@ -40,11 +42,59 @@ func DefaultErrorHandler() ErrorHandler[APIError, DefaultErrorType] {
 		r.Status = err.StatusCode
 
 		return DefaultErrorType{
-			Detail: err.Detail,
+			Detail:    err.Detail,
+			RequestID: RequestID(req.Context()),
 		}
 	}
 }
 
+// AdaptErrors converts request errors raised by goapi (bad parameters and bodies, rate limits) and unhandled
+// errors of endpoints into E, so they are answered by error handler of api. Without it they are answered
+// by DefaultErrorHandler whenever E cannot hold APIError, and documented with DefaultErrorType.
+func AdaptErrors[E error](api *API, adapt func(err APIError) E) {
+	if GetType[E]() != api.errorIn {
+		api.addRegistrationError("", "", "", fmt.Errorf("invalid error adapter, returns %s instead of %s", GetType[E](), api.errorIn))
+		return
+	}
+
+	handler := api.errorHandler
+
+	api.errorHandler = func(r Response, req *http.Request, err any) any {
+		if _, ok := err.(E); !ok {
+			return handler(r, req, adapt(asAPIError(req, err)))
+		}
+		return handler(r, req, err)
+	}
+
+	api.errorsAdapted = true
+}
+
+// asAPIError returns request error raised by goapi, other errors are logged and reported as internal server error
+func asAPIError(req *http.Request, err any) APIError {
+	if apiError, ok := err.(APIError); ok {
+		return apiError
+	}
+
+	slog.ErrorContext(req.Context(), "unhandled error",
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.String("error", fmt.Sprint(err)),
+	)
+
+	return NewAPIError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), nil)
+}
+
+// frameworkErrorScheme returns schema of request errors raised by goapi
+func (api *API) frameworkErrorScheme() (string, error) {
+	if api.errorScheme == "" || api.errorsAdapted || GetType[APIError]().AssignableTo(api.errorIn) {
+		return api.errorScheme, nil
+	}
+
+	schema, err := api.Schemas.RegisterSchema(GetType[DefaultErrorType]())
+
+	return schema.Name, err
+}
+
 func GetType[T any]() reflect.Type {
 	return reflect.TypeOf((*T)(nil)).Elem()
 }
@@ -2,22 +2,70 @@ package goapi
 
 import (
 	"embed"
+	"io"
 	"os"
+	"reflect"
 	"text/template"
 )
 
-//go:embed template.go.tmpl
+//go:embed template.go.tmpl client.go.tmpl types.ts.tmpl client.ts.tmpl server.go.tmpl
 var templateFS embed.FS
 
 func generate(api *API) error {
+	file, err := os.Create("openapi.yaml")
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	return writeSpec(api, file)
+}
+
+// WriteSpec validates registered routes and writes openapi document to w
+func (api *API) WriteSpec(w io.Writer) error {
+	if err := api.Validate(); err != nil {
+		return err
+	}
+
+	return writeSpec(api, w)
+}
+
+func writeSpec(api *API, w io.Writer) error {
+
+	frameworkScheme, err := api.frameworkErrorScheme()
+
+	if err != nil {
+		return err
+	}
 
 	errorName := func() string {
 		return api.errorScheme
 	}
 
+	meta := func(meta Meta, indent int) string {
+		return renderMeta(meta, indent, api.EnumComponents)
+	}
+
 	functions := template.FuncMap{
 		"schemePrefix": schemePrefix,
 		"errorName":    errorName,
+		"frameworkErrorName": func() string {
+			return frameworkScheme
+		},
+		"meta":        meta,
+		"enums":       func() []Schema { return enumComponents(api) },
+		"bodyOptions": func(method EndpointMethod) BodyOptions { return *api.bodyOptions(method.BodyOptions) },
+		"responseHeaders": func(method EndpointMethod) []headerSpec {
+			return append(corsHeaders(api, method), rateLimitHeaders(method)...)
+		},
+		"limited": func(method EndpointMethod) bool { return method.rateLimit != nil },
+		"retryHeaders": func(method EndpointMethod) []headerSpec {
+			headers := append(corsHeaders(api, method), rateLimitHeaders(method)...)
+			return append(headers, headerSpec{Name: "Retry-After", Type: JsonInteger})
+		},
+		"preflight": func(entry EndpointEntry) *corsPreflight { return preflightOf(api, entry) },
 	}
 
 	tmpl, err := template.New("template.go.tmpl").
@@ -28,19 +76,66 @@ func generate(api *API) error {
 		return err
 	}
 
-	file, err := os.Create("openapi.yaml")
+	err = tmpl.Execute(w, *api)
 
 	if err != nil {
 		return err
 	}
 
-	defer file.Close()
+	return nil
+}
 
-	err = tmpl.Execute(file, *api)
+// headerSpec documents response header
+type headerSpec struct {
+	Name string
+	Type JsonType
+}
 
-	if err != nil {
-		return err
+// enumComponents returns named enums used by api, as schemas
+func enumComponents(api *API) []Schema {
+	if !api.EnumComponents {
+		return nil
 	}
 
-	return nil
+	types := collectAPIEnums(api)
+	out := make([]Schema, 0, len(types))
+
+	for _, Type := range types {
+		meta, err := resolveMeta(Type)
+
+		if err != nil {
+			continue
+		}
+
+		meta.enumType = nil
+
+		out = append(out, Schema{
+			sourceType: Type,
+			Name:       Type.Name(),
+			Meta:       &meta,
+		})
+	}
+
+	return out
+}
+
+// collectAPIEnums returns named enum types used by schemas and parameters of api
+func collectAPIEnums(api *API) []reflect.Type {
+	var types []reflect.Type
+
+	for _, schema := range api.Schemas {
+		for _, prop := range schema.Properties {
+			types = collectEnums(prop.Meta, types)
+		}
+	}
+
+	for _, entry := range api.Endpoints {
+		for _, method := range entry.Methods {
+			for _, param := range method.Parameters {
+				types = collectEnums(param.Meta, types)
+			}
+		}
+	}
+
+	return types
 }
@@ -0,0 +1,312 @@
+package goapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/masnyjimmy/goapi"
+)
+
+// FuzzOptions configures Fuzz, zero values use defaults
+type FuzzOptions struct {
+	// Iterations per operation, 100 by default
+	Iterations int
+	// Seed of first iteration, iteration i uses Seed+i and failures report it
+	Seed uint64
+}
+
+// Fuzz sends random valid and near-valid requests, generated from documented parameters and bodies,
+// to every operation of api. It reports panics, server errors and responses not matching the document.
+// Failure of iteration is reproduced by FuzzOptions{Seed: seed, Iterations: 1}.
+func Fuzz(t testing.TB, api *goapi.API, opts FuzzOptions) {
+	t.Helper()
+
+	if opts.Iterations == 0 {
+		opts.Iterations = 100
+	}
+
+	f := newFuzzer(t, api)
+
+	for _, operation := range f.operations {
+		for i := range opts.Iterations {
+			f.run(t, operation, opts.Seed+uint64(i))
+		}
+	}
+}
+
+// FuzzTarget runs iteration of every operation per seed of native fuzzing,
+// for example func FuzzAPI(f *testing.F) { goapitest.FuzzTarget(f, newAPI()) }
+func FuzzTarget(f *testing.F, api *goapi.API) {
+	fuzz := newFuzzer(f, api)
+
+	for seed := range uint64(8) {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, seed uint64) {
+		for _, operation := range fuzz.operations {
+			fuzz.run(t, operation, seed)
+		}
+	})
+}
+
+type fuzzer struct {
+	api        *goapi.API
+	document   *goapi.SpecDocument
+	operations []fuzzOperation
+}
+
+type fuzzOperation struct {
+	index  uint64
+	path   string
+	method string
+	spec   *goapi.SpecOperation
+}
+
+func newFuzzer(t testing.TB, api *goapi.API) *fuzzer {
+	t.Helper()
+
+	document, err := api.SpecDocument()
+
+	if err != nil {
+		t.Fatalf("goapitest: %v", err)
+	}
+
+	f := &fuzzer{api: api, document: document}
+
+	for _, path := range slices.Sorted(maps.Keys(document.Paths)) {
+		for _, method := range slices.Sorted(maps.Keys(document.Paths[path])) {
+			if isPreflight(method, document.Paths[path][method]) {
+				continue
+			}
+
+			f.operations = append(f.operations, fuzzOperation{
+				index:  uint64(len(f.operations)),
+				path:   path,
+				method: method,
+				spec:   document.Paths[path][method],
+			})
+		}
+	}
+
+	return f
+}
+
+// isPreflight reports if operation is cors preflight documented by goapi, it is answered by cors handler
+func isPreflight(method string, operation *goapi.SpecOperation) bool {
+	return method == "options" && operation.OperationId == "" && operation.Summary == "CORS preflight"
+}
+
+var pathParam = regexp.MustCompile(`/[:*][^/]+`)
+
+// mutations of near-valid requests
+const (
+	mutateNone = iota
+	mutateDropParameter
+	mutateParameterValue
+	mutateBodySyntax
+	mutateBodyField
+)
+
+func (f *fuzzer) run(t testing.TB, operation fuzzOperation, seed uint64) {
+	t.Helper()
+
+	r := rand.New(rand.NewPCG(seed, operation.index))
+
+	mutation := mutateNone
+	if r.IntN(3) == 0 {
+		mutation = 1 + r.IntN(4)
+	}
+
+	req, body := f.request(operation, r, mutation)
+	recorder, panicValue := Serve(f.api.Handler(), req)
+
+	report := func(format string, args ...any) {
+		t.Helper()
+		t.Errorf("goapitest: %s %s (seed %d): %s\nrequest: %s %s\n%s",
+			strings.ToUpper(operation.method), operation.path, seed, fmt.Sprintf(format, args...), req.Method, req.URL, body)
+	}
+
+	if panicValue != nil {
+		report("panic: %v", panicValue)
+		return
+	}
+
+	if recorder.Code >= http.StatusInternalServerError {
+		report("server error %d: %s", recorder.Code, recorder.Body.Bytes())
+	}
+
+	if violations := f.document.ValidateResponse(operation.path, operation.method, recorder.Code, recorder.Body.Bytes()); len(violations) > 0 {
+		report("response does not match document: %s", strings.Join(violations, "; "))
+	}
+}
+
+// request builds random request of operation, applying mutation when operation has something to mutate
+func (f *fuzzer) request(operation fuzzOperation, r *rand.Rand, mutation int) (*http.Request, []byte) {
+	params := operation.spec.Parameters
+	mutated := -1
+
+	if (mutation == mutateDropParameter || mutation == mutateParameterValue) && len(params) > 0 {
+		mutated = r.IntN(len(params))
+	}
+
+	path := operation.path
+	query := url.Values{}
+	header := http.Header{}
+
+	for index, param := range params {
+		if !param.Required && r.IntN(2) == 0 && index != mutated {
+			continue
+		}
+
+		if index == mutated && mutation == mutateDropParameter {
+			continue
+		}
+
+		values := f.paramValues(param, r)
+
+		if index == mutated && mutation == mutateParameterValue {
+			values = map[string][]string{param.Name: {"~" + randomText(r)}}
+		}
+
+		for name, value := range values {
+			switch param.In {
+			case "path":
+				segment := value[0]
+				if segment == "" {
+					segment = "x"
+				}
+				path = strings.Replace(path, ":"+param.Name, url.PathEscape(segment), 1)
+			case "query":
+				query[name] = value
+			case "header":
+				header[http.CanonicalHeaderKey(name)] = value
+			case "cookie":
+				header.Add("Cookie", (&http.Cookie{Name: name, Value: url.QueryEscape(value[0])}).String())
+			}
+		}
+	}
+
+	// path parameters not generated by mutation are still needed to match route
+	path = pathParam.ReplaceAllString(path, "/x")
+
+	target := path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var body []byte
+
+	if requestBody := operation.spec.RequestBody; requestBody != nil {
+		schema := requestSchema(requestBody)
+		value := f.document.RandomValue(schema, r)
+
+		if fields, ok := value.(map[string]any); ok && mutation == mutateBodyField && len(fields) > 0 {
+			names := slices.Sorted(maps.Keys(fields))
+			fields[names[r.IntN(len(names))]] = []any{randomText(r)}
+		}
+
+		body, _ = json.Marshal(value)
+
+		if mutation == mutateBodySyntax {
+			body = body[:r.IntN(len(body))]
+		}
+	}
+
+	req := httptest.NewRequest(strings.ToUpper(operation.method), target, bytes.NewReader(body))
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, body
+}
+
+// paramValues returns raw values of parameter by name, serialized by its style
+func (f *fuzzer) paramValues(param goapi.SpecParameter, r *rand.Rand) map[string][]string {
+	value := f.document.RandomValue(param.Schema, r)
+
+	switch value := value.(type) {
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, rawValue(item))
+		}
+
+		explode := param.Explode == nil || *param.Explode
+
+		switch {
+		case len(items) == 0:
+			return map[string][]string{param.Name: {""}}
+		case param.In == "query" && (param.Style == "" || param.Style == "form") && explode:
+			return map[string][]string{param.Name: items}
+		case param.Style == "spaceDelimited":
+			return map[string][]string{param.Name: {strings.Join(items, " ")}}
+		case param.Style == "pipeDelimited":
+			return map[string][]string{param.Name: {strings.Join(items, "|")}}
+		}
+
+		return map[string][]string{param.Name: {strings.Join(items, ",")}}
+	case map[string]any:
+		out := map[string][]string{}
+		for key, item := range value {
+			if items, ok := item.([]any); ok {
+				for _, element := range items {
+					out[param.Name+"["+key+"]"] = append(out[param.Name+"["+key+"]"], rawValue(element))
+				}
+				continue
+			}
+			out[param.Name+"["+key+"]"] = []string{rawValue(item)}
+		}
+		return out
+	}
+
+	return map[string][]string{param.Name: {rawValue(value)}}
+}
+
+func requestSchema(body *goapi.SpecRequestBody) *goapi.SpecSchema {
+	if media, has := body.Content["application/json"]; has {
+		return media.Schema
+	}
+
+	for _, mediaType := range slices.Sorted(maps.Keys(body.Content)) {
+		return body.Content[mediaType].Schema
+	}
+
+	return nil
+}
+
+func rawValue(value any) string {
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+func randomText(r *rand.Rand) string {
+	const alphabet = "abc!@#$%^&*()_+-=[]{};':,.<>/? 0123456789é世"
+
+	runes := []rune(alphabet)
+	out := make([]rune, 1+r.IntN(10))
+
+	for i := range out {
+		out[i] = runes[r.IntN(len(runes))]
+	}
+
+	return string(out)
+}
@@ -0,0 +1,85 @@
+package goapitest_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/masnyjimmy/goapi/goapitest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Limit int
+
+func (Limit) Spec() goapi.Spec {
+	return goapi.Spec{Name: "limit", Required: true}
+}
+
+type Order string
+
+func (Order) Spec() goapi.Spec {
+	return goapi.Spec{Name: "order"}
+}
+
+func (Order) Enum() []any {
+	return []any{"asc", "desc"}
+}
+
+type Page struct {
+	Items []int `json:"items"`
+}
+
+func ListItems(limit Limit, order Order) (Page, goapi.APIError) {
+	return Page{Items: make([]int, 0, max(0, int(limit)))}, nil
+}
+
+func TestFuzz(t *testing.T) {
+	api := newAPI()
+	appRouter := api.Router()
+	appRouter.Get("/items", ListItems, goapi.RouteSpec{})
+
+	// panicking route of newAPI is not registered by this api
+	healthy := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	healthyRouter := healthy.Router()
+	healthyRouter.Post("/calculate", Calculate, goapi.RouteSpec{})
+	healthyRouter.Get("/items", ListItems, goapi.RouteSpec{})
+
+	goapitest.Fuzz(t, &healthy, goapitest.FuzzOptions{Iterations: 200})
+
+	failures := &recorder{TB: t}
+	goapitest.Fuzz(failures, api, goapitest.FuzzOptions{Iterations: 5})
+
+	assert.Len(t, failures.errors, 5)
+	for _, failure := range failures.errors {
+		assert.True(t, strings.HasPrefix(failure, "goapitest: GET /panic (seed "), failure)
+		assert.Contains(t, failure, "panic: boom")
+	}
+}
+
+func Teapot(r goapi.Response) (Result, goapi.APIError) {
+	r.Status = http.StatusTeapot
+	return Result{}, nil
+}
+
+func TestFuzzUndocumentedResponse(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	appRouter.Get("/teapot", Teapot, goapi.RouteSpec{})
+
+	failures := &recorder{TB: t}
+	goapitest.Fuzz(failures, &api, goapitest.FuzzOptions{Iterations: 1})
+
+	if assert.Len(t, failures.errors, 1) {
+		assert.Contains(t, failures.errors[0], "response does not match document: response 418: undocumented status")
+	}
+}
+
+func FuzzItems(f *testing.F) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	appRouter.Get("/items", ListItems, goapi.RouteSpec{})
+
+	goapitest.FuzzTarget(f, &api)
+}
@@ -0,0 +1,226 @@
+// Package goapitest calls goapi endpoints in process, through router and route middleware of the api.
+package goapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/masnyjimmy/goapi"
+)
+
+var update = flag.Bool("goapitest.update", false, "rewrite golden files of goapitest calls")
+
+// Response is response of call, Value is decoded body of successful response
+type Response[T any] struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	Value  T
+}
+
+// Option configures call and its expectations
+type Option func(*call)
+
+type call struct {
+	header  http.Header
+	status  int
+	headers http.Header
+	golden  string
+}
+
+// WithHeader sets header of request
+func WithHeader(key, value string) Option {
+	return func(c *call) {
+		c.header.Add(key, value)
+	}
+}
+
+// ExpectStatus fails test when response has other status
+func ExpectStatus(status int) Option {
+	return func(c *call) {
+		c.status = status
+	}
+}
+
+// ExpectHeader fails test when response header has other value
+func ExpectHeader(key, value string) Option {
+	return func(c *call) {
+		c.headers.Add(key, value)
+	}
+}
+
+// Golden compares request and response with testdata/name.golden, rewritten when -goapitest.update is set
+func Golden(name string) Option {
+	return func(c *call) {
+		c.golden = name
+	}
+}
+
+// Call sends request to api and decodes body of 2xx response into T. Body is encoded as json,
+// unless it is []byte or string sent as is, nil sends no body.
+func Call[T any](t testing.TB, api *goapi.API, method, path string, body any, opts ...Option) *Response[T] {
+	t.Helper()
+
+	c := call{header: make(http.Header), headers: make(http.Header)}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	payload, err := encodeBody(body)
+
+	if err != nil {
+		t.Fatalf("goapitest: encode body of %s %s: %v", method, path, err)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+
+	if payload != nil && c.header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+
+	recorder, panicValue := Serve(api.Handler(), req)
+
+	if panicValue != nil {
+		t.Fatalf("goapitest: %s %s panicked: %v", method, path, panicValue)
+	}
+
+	response := &Response[T]{
+		Status: recorder.Code,
+		Header: recorder.Header(),
+		Body:   recorder.Body.Bytes(),
+	}
+
+	if c.status != 0 && response.Status != c.status {
+		t.Errorf("goapitest: %s %s: expected status %d, got %d: %s", method, path, c.status, response.Status, response.Body)
+	}
+
+	for key, values := range c.headers {
+		if got := response.Header.Values(key); strings.Join(got, ", ") != strings.Join(values, ", ") {
+			t.Errorf("goapitest: %s %s: expected header %s %q, got %q", method, path, key, values, got)
+		}
+	}
+
+	if response.Status >= 200 && response.Status < 300 && len(bytes.TrimSpace(response.Body)) > 0 {
+		if err := json.Unmarshal(response.Body, &response.Value); err != nil {
+			t.Errorf("goapitest: %s %s: decode response: %v", method, path, err)
+		}
+	}
+
+	if c.golden != "" {
+		checkGolden(t, c.golden, req, payload, response.Status, response.Header, response.Body)
+	}
+
+	return response
+}
+
+// ErrorAs decodes body of error response into E, usually error type of api
+func ErrorAs[E, T any](t testing.TB, response *Response[T]) E {
+	t.Helper()
+
+	var out E
+
+	if response.Status < 400 {
+		t.Errorf("goapitest: expected error response, got status %d", response.Status)
+		return out
+	}
+
+	if err := json.Unmarshal(response.Body, &out); err != nil {
+		t.Errorf("goapitest: decode error response: %v", err)
+	}
+
+	return out
+}
+
+// Serve serves request by handler, panics of handler are recovered and returned
+func Serve(handler http.Handler, req *http.Request) (recorder *httptest.ResponseRecorder, panicValue any) {
+	recorder = httptest.NewRecorder()
+
+	defer func() {
+		panicValue = recover()
+	}()
+
+	handler.ServeHTTP(recorder, req)
+
+	return recorder, nil
+}
+
+func encodeBody(body any) ([]byte, error) {
+	switch body := body.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return body, nil
+	case string:
+		return []byte(body), nil
+	}
+
+	return json.Marshal(body)
+}
+
+// checkGolden compares recorded exchange with golden file, json bodies are indented so diffs are readable
+func checkGolden(t testing.TB, name string, req *http.Request, payload []byte, status int, header http.Header, body []byte) {
+	t.Helper()
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s\n", req.Method, req.URL.RequestURI())
+	writeExchangeBody(&b, req.Header.Get("Content-Type"), payload)
+	fmt.Fprintf(&b, "\n%d %s\n", status, http.StatusText(status))
+	writeExchangeBody(&b, header.Get("Content-Type"), body)
+
+	file := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("goapitest: %v", err)
+		}
+
+		if err := os.WriteFile(file, []byte(b.String()), 0o644); err != nil {
+			t.Fatalf("goapitest: %v", err)
+		}
+
+		return
+	}
+
+	expected, err := os.ReadFile(file)
+
+	if err != nil {
+		t.Fatalf("goapitest: %v, run with -goapitest.update to record it", err)
+	}
+
+	if string(expected) != b.String() {
+		t.Errorf("goapitest: exchange differs from %s\n--- expected\n%s\n--- actual\n%s", file, expected, b.String())
+	}
+}
+
+func writeExchangeBody(w io.Writer, contentType string, body []byte) {
+	if contentType != "" {
+		fmt.Fprintf(w, "Content-Type: %s\n", contentType)
+	}
+
+	if len(body) == 0 {
+		return
+	}
+
+	var indented bytes.Buffer
+
+	if err := json.Indent(&indented, body, "", "  "); err == nil {
+		body = indented.Bytes()
+	}
+
+	fmt.Fprintf(w, "\n%s\n", body)
+}
@@ -0,0 +1,99 @@
+package goapitest_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/masnyjimmy/goapi/goapitest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Calculation struct {
+	Left  int `json:"left"`
+	Right int `json:"right"`
+}
+
+type Result struct {
+	Result int `json:"result"`
+}
+
+func Calculate(calc Calculation) (Result, goapi.APIError) {
+	if calc.Left < 0 || calc.Right < 0 {
+		return Result{}, goapi.NewAPIError(http.StatusBadRequest, "negative numbers", http.Header{"X-Reason": {"negative"}})
+	}
+
+	return Result{Result: calc.Left + calc.Right}, nil
+}
+
+// recorder collects failures reported through testing.TB, instead of failing the test running it
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// Fatalf is recorded without stopping goroutine, reported failures are checked by test
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func (r *recorder) Failed() bool {
+	return len(r.errors) > 0
+}
+
+func newAPI() *goapi.API {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{})
+	appRouter.Get("/panic", func() (Result, goapi.APIError) { panic("boom") }, goapi.RouteSpec{})
+
+	return &api
+}
+
+func TestCall(t *testing.T) {
+	api := newAPI()
+
+	response := goapitest.Call[Result](t, api, "POST", "/calculate", Calculation{Left: 1, Right: 2},
+		goapitest.ExpectStatus(http.StatusOK),
+		goapitest.Golden("calculate"),
+	)
+
+	assert.Equal(t, Result{Result: 3}, response.Value)
+
+	response = goapitest.Call[Result](t, api, "POST", "/calculate", Calculation{Left: -1},
+		goapitest.ExpectStatus(http.StatusBadRequest),
+		goapitest.ExpectHeader("X-Reason", "negative"),
+	)
+
+	assert.Equal(t, Result{}, response.Value, "Error decoded as result")
+	assert.Equal(t, "negative numbers", goapitest.ErrorAs[goapi.DefaultErrorType](t, response).Detail)
+}
+
+func TestCallExpectations(t *testing.T) {
+	api := newAPI()
+	failures := &recorder{TB: t}
+
+	goapitest.Call[Result](failures, api, "POST", "/calculate", `{"left": "x"}`, goapitest.ExpectStatus(http.StatusOK))
+	assert.True(t, failures.Failed(), "Unexpected status not reported")
+
+	failures = &recorder{TB: t}
+
+	goapitest.Call[Result](failures, api, "GET", "/panic", nil)
+	assert.Equal(t, []string{"goapitest: GET /panic panicked: boom"}, failures.errors)
+}
+
+func TestServePanic(t *testing.T) {
+	recorder, panicValue := goapitest.Serve(newAPI().Handler(), httptest.NewRequest("GET", "/panic", nil))
+
+	assert.Equal(t, "boom", panicValue)
+	assert.NotNil(t, recorder)
+}
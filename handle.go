@@ -1,47 +1,70 @@
 package goapi
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"reflect"
-	"strconv"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/julienschmidt/httprouter"
 )
 
 var ErrInvalidValueFormat = errors.New("invalid value format")
 
-func parseValue(value string, t JsonType) (reflect.Value, error) {
-	switch t {
-	case JsonBoolean:
-		switch value {
-		case "true":
-			return reflect.ValueOf(true), nil
-		case "false":
-			return reflect.ValueOf(false), nil
-		default:
-			return reflect.Value{}, fmt.Errorf("invalid boolean value: %s", value)
+// lookupValues returns raw values of parameter from its location
+func lookupValues(el *HandleParam, req *http.Request, params httprouter.Params, query url.Values) []string {
+	switch el.In {
+	case ParamPath, ParamQuery:
+		if value := params.ByName(el.Name); value != "" {
+			return []string{value}
 		}
-	case JsonInteger:
-		val, err := strconv.ParseInt(value, 10, 0)
-		if err != nil {
-			return reflect.Value{}, fmt.Errorf("invalid integer value: %s", value)
+		return query[el.Name]
+	case ParamHeader:
+		return req.Header.Values(el.Name)
+	case ParamCookie:
+		if cookie, err := req.Cookie(el.Name); err == nil {
+			return []string{cookie.Value}
 		}
-		return reflect.ValueOf(val), nil
-	case JsonNumber:
-		val, err := strconv.ParseFloat(value, 32)
-		if err != nil {
-			return reflect.Value{}, err
+	}
+
+	return nil
+}
+
+// splitValues splits raw array values by delimiter of style, exploded values are already split
+func splitValues(values []string, style ParamStyle, explode bool) []string {
+	if explode && style != StyleSimple {
+		return values
+	}
+
+	separator := ","
+
+	switch style {
+	case StyleSpaceDelimited:
+		separator = " "
+	case StylePipeDelimited:
+		separator = "|"
+	}
+
+	out := make([]string, 0, len(values))
+
+	for _, value := range values {
+		for part := range strings.SplitSeq(value, separator) {
+			if style == StyleSimple {
+				part = strings.TrimSpace(part)
+			}
+			out = append(out, part)
 		}
-		return reflect.ValueOf(val), nil
-	case JsonString:
-		return reflect.ValueOf(value), nil
 	}
 
-	panic("invalid json type")
+	return out
 }
 
 type HandleParam struct {
@@ -49,217 +72,374 @@ type HandleParam struct {
 	JsonType JsonType
 	Required bool
 	Name     string
+	Style    ParamStyle
+	Explode  bool
+	Default  []string
 	Special  bool
+
+	// binding plan, compiled at registration
+	paramType  reflect.Type
+	parse      valueParser
+	deepObject deepObjectParser
+	body       *schemaPlan
 }
 
 type HandleData struct {
 	Endpoint Endpoint
 	Params   []HandleParam
+	Body     *BodyOptions
 }
 
-func DebugLol()
-{
+// buffers reused for reading bodies and encoding responses
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+const maxPooledBuffer = 64 << 10
 
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
 }
 
-func makeRouterHandle(api *API, data HandleData) httprouter.Handle {
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBuffer {
+		bufferPool.Put(buf)
+	}
+}
 
-	errorHandler := api.errorHandler
+// writeJSON encodes value before writing headers, so encoding errors do not leave partial response
+func writeJSON(w http.ResponseWriter, status int, headers http.Header, value any) {
+	buf := getBuffer()
+	defer putBuffer(buf)
 
-	return func(
-		w http.ResponseWriter,
-		req *http.Request,
-		params httprouter.Params,
-	) {
-		DebugLol()
-		endpointType := reflect.TypeOf(data.Endpoint)
-		out := make([]reflect.Value, len(data.Params))
-		bodyParams := make([]int, 0)
+	if err := json.NewEncoder(buf).Encode(value); err != nil {
+		panic(err)
+	}
 
-		response := newResponse(&w, false)
+	// apply headers from response
+	for key, values := range headers {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
 
-		for index, el := range data.Params {
-			paramType := endpointType.In(index)
+	w.WriteHeader(status)
 
-			// handle special types first
+	// encoder terminates value with new line, json.Marshal does not
+	if _, err := w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))); err != nil {
+		panic(err)
+	}
+}
 
-			switch el.In {
-			case ParamUndefined:
-				switch paramType {
-				case GetType[Response]():
-					out[index] = reflect.ValueOf(response)
-				}
-			case ParamPath, ParamQuery: // parameter
-				value := params.ByName(el.Name)
+// api error handling
+func writeError(w http.ResponseWriter, req *http.Request, errorHandler genericErrorHandler, value any) {
+	_, span := StartSpan(req.Context(), "error")
+	defer span.Finish()
 
-				if value == "" {
-					value = req.URL.Query().Get(el.Name)
-				}
+	span.RecordError(value)
+	SpanFromContext(req.Context()).RecordError(value)
 
-				if value == "" {
-					if el.Required {
-						panic("Required but not provided")
-						//TODO: handle http error, (invalid parameters)
-					} else {
-						out[index] = reflect.Zero(paramType)
-					}
-				} else {
-					parsedValue, err := parseValue(value, el.JsonType)
+	// error detail is logged by access log
+	if info := requestInfoOf(req.Context()); info != nil {
+		if apiError, ok := value.(APIError); ok {
+			info.err = apiError.Detail
+		} else {
+			info.err = fmt.Sprint(value)
+		}
+	}
 
-					if err != nil {
-						panic("Unable to parse value")
-						//TODO: handle http error, invalid request or som
-					}
+	errorResponse := newResponse(&w, true)
+	result := errorHandler(errorResponse, req, value)
 
-					out[index] = parsedValue.Convert(paramType)
-				}
-			case ParamHeader: // header
-				value := req.Header.Get(el.Name)
-
-				if value == "" {
-					if el.Required {
-						panic("Unhandled: el.Required but not provided")
-					} else {
-						out[index] = reflect.Zero(paramType)
-					}
-				} else {
-					parsedValue, err := parseValue(value, el.JsonType)
+	writeJSON(w, errorResponse.Status, errorResponse.Headers, result)
+}
 
-					if err != nil {
-						panic("unable to parse value")
-					}
+// request errors are reported by error handler as APIError
+func invalidRequest(w http.ResponseWriter, req *http.Request, errorHandler genericErrorHandler, status int, format string, args ...any) {
+	writeError(w, req, errorHandler, NewAPIError(status, fmt.Sprintf(format, args...), nil))
+}
 
-					out[index] = parsedValue.Convert(paramType)
-				}
-			case ParamCookie:
-				cookie, err := req.Cookie(el.Name)
+// requestBinder binds request into values of endpoint inputs following compiled plan
+type requestBinder struct {
+	api           *API
+	params        []HandleParam
+	body          *BodyOptions
+	needsQuery    bool
+	bodyParams    []int
+	contextParams []int
+}
 
-				if err != nil {
-					if el.Required {
-						panic("Required but not provided")
-					} else {
-						out[index] = reflect.Zero(paramType)
-					}
-				} else {
-					parsedValue, err := parseValue(cookie.Value, el.JsonType)
+func newRequestBinder(api *API, params []HandleParam, body *BodyOptions) *requestBinder {
+	binder := &requestBinder{
+		api:        api,
+		params:     params,
+		body:       body,
+		bodyParams: make([]int, 0),
+	}
 
-					if err != nil {
-						panic("unable to parse value")
-					}
+	// query is parsed once, only when some parameter may read it
+	for index, el := range params {
+		switch el.In {
+		case ParamPath, ParamQuery:
+			binder.needsQuery = true
+		case ParamBody:
+			binder.bodyParams = append(binder.bodyParams, index)
+		case ParamUndefined:
+			if el.paramType == GetType[context.Context]() {
+				binder.contextParams = append(binder.contextParams, index)
+			}
+		}
+	}
 
-					out[index] = parsedValue.Convert(paramType)
+	return binder
+}
+
+// bind returns values of inputs and response, reports false if request was rejected
+func (b *requestBinder) bind(
+	w http.ResponseWriter,
+	req *http.Request,
+	params httprouter.Params,
+) ([]reflect.Value, Response, bool) {
+	// error handler is resolved per request, so errors may be adapted after routes
+	errorHandler := b.api.errorHandler
+	out := make([]reflect.Value, len(b.params))
+
+	ctx, span := StartSpan(req.Context(), "bind")
+	defer span.Finish()
+
+	var query url.Values
+	if b.needsQuery {
+		query = req.URL.Query()
+	}
+
+	response := newResponse(&w, false)
+
+	for index := range b.params {
+		el := &b.params[index]
+
+		// handle special types first
+
+		switch el.In {
+		case ParamUndefined:
+			switch el.paramType {
+			case GetType[Response]():
+				out[index] = reflect.ValueOf(response)
+			case GetType[context.Context]():
+				out[index] = reflect.ValueOf(req.Context())
+			}
+		case ParamPath, ParamQuery, ParamHeader, ParamCookie: // parameter
+			if el.deepObject != nil {
+				value, found, err := el.deepObject(query, el.Name)
+
+				if err != nil {
+					invalidRequest(w, req, errorHandler, http.StatusUnprocessableEntity, "invalid value of parameter (%s): %v", el.Name, err)
+					return nil, nil, false
+				}
+
+				if !found && el.Required {
+					invalidRequest(w, req, errorHandler, http.StatusUnprocessableEntity, "missing required parameter (%s)", el.Name)
+					return nil, nil, false
 				}
-			case ParamBody: // parse json
-				bodyParams = append(bodyParams, index)
+
+				out[index] = value
+				continue
 			}
-		}
 
-		if bc := len(bodyParams); bc == 1 {
-			index := bodyParams[0]
-			value := reflect.New(endpointType.In(index)).Interface()
-			err := json.NewDecoder(req.Body).Decode(&value)
+			values := lookupValues(el, req, params, query)
 
-			if err != nil {
-				panic("cannot decode")
-				//TODO: handle
+			if len(values) == 0 || values[0] == "" {
+				values = el.Default
 			}
 
-			out[index] = reflect.ValueOf(value).Elem()
-		} else if bc > 1 {
-			schemes := make(map[string]any)
+			if len(values) == 0 {
+				if el.Required {
+					invalidRequest(w, req, errorHandler, http.StatusUnprocessableEntity, "missing required parameter (%s)", el.Name)
+					return nil, nil, false
+				}
+
+				out[index] = reflect.Zero(el.paramType)
+				continue
+			}
 
-			for _, bodyIndex := range bodyParams {
-				prefix := schemePrefix(data.Params[bodyIndex].Name)
-				value := reflect.New(endpointType.In(bodyIndex)).Elem().Interface()
-				schemes[prefix] = value
+			if el.JsonType == JsonArray {
+				values = splitValues(values, el.Style, el.Explode)
 			}
-			bodyBytes, err := io.ReadAll(req.Body)
+
+			parsedValue, err := el.parse(values)
 
 			if err != nil {
-				panic("Unable to read body")
+				invalidRequest(w, req, errorHandler, http.StatusUnprocessableEntity, "invalid value of parameter (%s): %v", el.Name, err)
+				return nil, nil, false
 			}
 
-			var rawSchemes map[string]json.RawMessage
-			if err := json.Unmarshal(bodyBytes, &rawSchemes); err != nil {
-				panic("cannot unmarshal")
+			out[index] = parsedValue
+		}
+	}
+
+	if bc := len(b.bodyParams); bc > 0 {
+		_, decodeSpan := StartSpan(ctx, "decode")
+		defer decodeSpan.Finish()
+
+		// api-wide options are resolved per request, so they may be set after routes
+		options := b.api.bodyOptions(b.body)
+
+		if !options.acceptsContentType(req) {
+			invalidRequest(w, req, errorHandler, http.StatusUnsupportedMediaType, "unsupported media type (%s)", req.Header.Get("Content-Type"))
+			return nil, nil, false
+		}
+
+		if options.MaxBytes > 0 {
+			req.Body = http.MaxBytesReader(w, req.Body, options.MaxBytes)
+		}
+
+		if bc == 1 {
+			index := b.bodyParams[0]
+			el := &b.params[index]
+			value := reflect.New(el.paramType)
+
+			if err := el.body.applyDefaults(value.Elem()); err != nil {
+				invalidRequest(w, req, errorHandler, http.StatusInternalServerError, "%v", err)
+				return nil, nil, false
 			}
 
-			for _, bodyIndex := range bodyParams {
-				prefix := schemePrefix(data.Params[bodyIndex].Name)
+			body := io.Reader(req.Body)
+			var fields map[string]json.RawMessage
 
-				rawJSON, ok := rawSchemes[prefix]
-				if !ok {
-					continue
-				}
+			// raw body is read only for schemas checking presence of fields
+			if el.body.readsFields(options) {
+				buf := getBuffer()
+				defer putBuffer(buf)
 
-				value := reflect.New(endpointType.In(bodyIndex))
-				if err := json.Unmarshal(rawJSON, value.Interface()); err != nil {
-					panic("cannot unmarshal to target type")
+				if _, err := buf.ReadFrom(req.Body); err != nil {
+					invalidRequest(w, req, errorHandler, bodyErrorStatus(err), "%s", bodyErrorDetail(err))
+					return nil, nil, false
 				}
 
-				out[bodyIndex] = value.Elem()
+				fields = rawFields(buf.Bytes())
+				body = bytes.NewReader(buf.Bytes())
+			}
+
+			if err := options.decode(body, value.Interface()); err != nil {
+				invalidRequest(w, req, errorHandler, bodyErrorStatus(err), "%s", bodyErrorDetail(err))
+				return nil, nil, false
 			}
-		}
-		ret := reflect.ValueOf(data.Endpoint).Call(out)
 
-		// api error handling
-		handleError := func(value reflect.Value) bool {
-			if value.IsNil() {
-				return false
+			if options.RequireFields {
+				if err := el.body.checkRequired(fields); err != nil {
+					invalidRequest(w, req, errorHandler, http.StatusUnprocessableEntity, "%v", err)
+					return nil, nil, false
+				}
 			}
 
-			errorResponse := newResponse(&w, true)
-			result := errorHandler(errorResponse, req, value.Interface())
+			if err := el.body.validate(value.Elem(), fields); err != nil {
+				invalidRequest(w, req, errorHandler, http.StatusUnprocessableEntity, "%v", err)
+				return nil, nil, false
+			}
 
-			bytes, err := json.Marshal(result)
+			out[index] = value.Elem()
+		} else {
+			buf := getBuffer()
+			defer putBuffer(buf)
 
-			if err != nil {
-				panic(err)
+			if _, err := buf.ReadFrom(req.Body); err != nil {
+				invalidRequest(w, req, errorHandler, bodyErrorStatus(err), "%s", bodyErrorDetail(err))
+				return nil, nil, false
 			}
 
-			// apply headers from response
-			for key, values := range errorResponse.Headers {
-				for _, value := range values {
-					w.Header().Add(key, value)
+			var rawSchemes map[string]json.RawMessage
+			if err := json.Unmarshal(buf.Bytes(), &rawSchemes); err != nil {
+				invalidRequest(w, req, errorHandler, http.StatusBadRequest, "invalid request body: %v", err)
+				return nil, nil, false
+			}
+
+			if options.DisallowUnknownFields {
+				for prefix := range rawSchemes {
+					if !slices.ContainsFunc(b.bodyParams, func(index int) bool { return schemePrefix(b.params[index].Name) == prefix }) {
+						invalidRequest(w, req, errorHandler, http.StatusBadRequest, "invalid request body: unknown field %q", prefix)
+						return nil, nil, false
+					}
 				}
 			}
 
-			w.WriteHeader(errorResponse.Status)
+			for _, bodyIndex := range b.bodyParams {
+				el := &b.params[bodyIndex]
+				prefix := schemePrefix(el.Name)
 
-			if _, err := w.Write(bytes); err != nil {
-				panic(err)
-			}
+				value := reflect.New(el.paramType)
+				if err := el.body.applyDefaults(value.Elem()); err != nil {
+					invalidRequest(w, req, errorHandler, http.StatusInternalServerError, "%v", err)
+					return nil, nil, false
+				}
 
-			return true
-		}
+				if rawJSON, ok := rawSchemes[prefix]; ok {
+					if err := options.decode(bytes.NewReader(rawJSON), value.Interface()); err != nil {
+						invalidRequest(w, req, errorHandler, http.StatusBadRequest, "invalid request body (%s): %v", prefix, err)
+						return nil, nil, false
+					}
+				}
 
-		// handle error, if no error and any value then send value
-		errorHandled := false
+				fields := rawFields(rawSchemes[prefix])
 
-		switch len(ret) {
-		case 1:
-			errorHandled = handleError(ret[0])
-		case 2:
-			errorHandled = handleError(ret[1])
-		}
+				if options.RequireFields {
+					if err := el.body.checkRequired(fields); err != nil {
+						invalidRequest(w, req, errorHandler, http.StatusUnprocessableEntity, "%s: %v", prefix, err)
+						return nil, nil, false
+					}
+				}
 
-		if !errorHandled {
-			// apply headers from response
-			for key, values := range response.Headers {
-				for _, value := range values {
-					w.Header().Add(key, value)
+				if err := el.body.validate(value.Elem(), fields); err != nil {
+					invalidRequest(w, req, errorHandler, http.StatusUnprocessableEntity, "%v", err)
+					return nil, nil, false
 				}
-			}
-			w.WriteHeader(response.Status)
 
-			bytes, err := json.Marshal(ret[0].Interface())
-			if err != nil {
-				panic(err)
+				out[bodyIndex] = value.Elem()
 			}
-			if _, err := w.Write(bytes); err != nil {
-				panic(err)
+		}
+	}
+
+	return out, response, true
+}
+
+func makeRouterHandle(api *API, data HandleData) httprouter.Handle {
+
+	endpoint := reflect.ValueOf(data.Endpoint)
+	binder := newRequestBinder(api, data.Params, data.Body)
+
+	return func(
+		w http.ResponseWriter,
+		req *http.Request,
+		params httprouter.Params,
+	) {
+		out, response, ok := binder.bind(w, req, params)
+
+		if !ok {
+			return
+		}
+
+		ctx, span := StartSpan(req.Context(), "call")
+
+		// endpoints taking context get span of call
+		if span != nil {
+			for _, index := range binder.contextParams {
+				out[index] = reflect.ValueOf(ctx)
 			}
 		}
 
+		ret := endpoint.Call(out)
+		span.Finish()
+
+		// handle error, if no error and any value then send value
+		errValue := ret[len(ret)-1]
+
+		if !errValue.IsNil() {
+			writeError(w, req, api.errorHandler, errValue.Interface())
+			return
+		}
+
+		writeJSON(w, response.Status, response.Headers, ret[0].Interface())
 	}
 }
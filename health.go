@@ -0,0 +1,163 @@
+package goapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultHealthTimeout = 2 * time.Second
+
+// HealthCheck is named check of service dependency, run by readiness route
+type HealthCheck struct {
+	Name string
+	// Timeout limits duration of check, defaults to 2s
+	Timeout time.Duration
+	Check   func(ctx context.Context) error
+}
+
+type HealthStatus string
+
+const (
+	HealthUp   HealthStatus = "up"
+	HealthDown HealthStatus = "down"
+)
+
+func (HealthStatus) Enum() []any {
+	return []any{HealthUp, HealthDown}
+}
+
+// CheckResult is outcome of single health check
+type CheckResult struct {
+	Status    HealthStatus `json:"status"`
+	LatencyMs float64      `json:"latencyMs"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// HealthChecks are results of health checks by name
+type HealthChecks map[string]CheckResult
+
+func (HealthChecks) JSONSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"additionalProperties": map[string]any{
+			"type":     "object",
+			"required": []any{"status", "latencyMs"},
+			"properties": map[string]any{
+				"status":    map[string]any{"type": "string", "enum": HealthStatus("").Enum()},
+				"latencyMs": map[string]any{"type": "number", "format": "double"},
+				"error":     map[string]any{"type": "string"},
+			},
+		},
+	}
+}
+
+// HealthReport is response of health routes
+type HealthReport struct {
+	Status HealthStatus `json:"status"`
+	Checks HealthChecks `json:"checks"`
+}
+
+type healthInput struct {
+	Response Response
+}
+
+// Health registers liveness (/healthz) and readiness (/readyz) routes on router.
+// Readiness runs checks concurrently and responds 503 if any of them fails.
+// Routes are tagged by HealthTags only.
+func (api *API) Health(router *Router, checks ...HealthCheck) {
+	spec := func(summary string, operationId string) RouteSpec {
+		return RouteSpec{
+			Tags:        api.HealthTags,
+			Summary:     summary,
+			OperationId: operationId,
+			Responses: []ResponseEntry{{
+				Status:      "503",
+				Description: "Service Unavailable",
+				Schema:      "HealthReport",
+			}},
+			untagged: true,
+		}
+	}
+
+	Get(router, "/healthz", func(ctx context.Context, in healthInput) (HealthReport, error) {
+		return HealthReport{Status: HealthUp, Checks: HealthChecks{}}, nil
+	}, spec("Liveness", "liveness"))
+
+	Get(router, "/readyz", func(ctx context.Context, in healthInput) (HealthReport, error) {
+		report := runChecks(ctx, checks)
+
+		if report.Status != HealthUp {
+			in.Response.Status = http.StatusServiceUnavailable
+		}
+
+		return report, nil
+	}, spec("Readiness", "readiness"))
+}
+
+// runChecks runs checks concurrently, checks not respecting context are abandoned on timeout
+func runChecks(ctx context.Context, checks []HealthCheck) HealthReport {
+	report := HealthReport{
+		Status: HealthUp,
+		Checks: make(HealthChecks, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, check := range checks {
+		wg.Go(func() {
+			result := runCheck(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[check.Name] = result
+			if result.Status != HealthUp {
+				report.Status = HealthDown
+			}
+		})
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+func runCheck(ctx context.Context, check HealthCheck) CheckResult {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = defaultHealthTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- check.Check(ctx)
+	}()
+
+	var err error
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{
+		Status:    HealthUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		result.Status = HealthDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,65 @@
+package goapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	checks := []goapi.HealthCheck{
+		{Name: "database", Check: func(ctx context.Context) error { return nil }},
+		{Name: "cache", Check: func(ctx context.Context) error { return errors.New("connection refused") }},
+		{Name: "queue", Timeout: 10 * time.Millisecond, Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	}
+
+	appRouter.AddRoute("/ops", func(r *goapi.Router) {
+		api.Health(r, checks...)
+	})
+
+	get := func(path string) (int, goapi.HealthReport) {
+		recorder := httptest.NewRecorder()
+		api.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+
+		var report goapi.HealthReport
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+
+		return recorder.Code, report
+	}
+
+	status, report := get("/ops/healthz")
+	assert.Exactly(t, http.StatusOK, status)
+	assert.Equal(t, goapi.HealthUp, report.Status)
+
+	status, report = get("/ops/readyz")
+	assert.Exactly(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, goapi.HealthDown, report.Status)
+	assert.Len(t, report.Checks, 3)
+	assert.Equal(t, goapi.HealthUp, report.Checks["database"].Status)
+	assert.Equal(t, "connection refused", report.Checks["cache"].Error)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["queue"].Error)
+
+	assert.Empty(t, api.Tags, "Health routes must not be tagged")
+
+	t.Chdir(t.TempDir())
+	assert.NoError(t, api.Setup())
+
+	spec, err := os.ReadFile("openapi.yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(spec), "'503':")
+}
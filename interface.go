@@ -1,6 +1,7 @@
 package goapi
 
 import (
+	"encoding"
 	"reflect"
 	"sync"
 )
@@ -11,16 +12,50 @@ type Spec struct {
 	Description string
 }
 
-type paramType interface {
+// ParamSpecifier names and describes a parameter type
+type ParamSpecifier interface {
 	Spec() Spec
 }
 
-type paramWithFormat interface {
+// Locator places a parameter in path, query, header or cookie
+type Locator interface {
+	In() ParamIn
+}
+
+// Styler sets serialization style of a parameter
+type Styler interface {
+	Style() ParamStyle
+}
+
+// Exploder sets explode flag of a parameter
+type Exploder interface {
+	Explode() bool
+}
+
+// ParamParser is implemented by types parsing raw parameter values by themselves,
+// it takes precedence over encoding.TextUnmarshaler
+type ParamParser interface {
+	ParseParam(value string) error
+}
+
+// Formatter sets format of a type schema
+type Formatter interface {
 	Format() string
 }
 
-type paramWithIn interface {
-	In() ParamIn
+// Enumerator lists allowed values of a type
+type Enumerator interface {
+	Enum() []any
+}
+
+// Defaulter provides raw default value of a type, parsed as it would be sent by request
+type Defaulter interface {
+	Default() string
+}
+
+// SchemaProvider replaces generated json schema of a type
+type SchemaProvider interface {
+	JSONSchema() map[string]any
 }
 
 func getInterface[T any]() reflect.Type {
@@ -79,24 +114,70 @@ func resolveInterfaceInstance[T any](t reflect.Type) (any, bool) {
 }
 
 func extractSpec(t reflect.Type) *Spec {
-	if value, ok := resolveInterfaceInstance[paramType](t); ok {
-		spec := value.(paramType).Spec()
+	if value, ok := resolveInterfaceInstance[ParamSpecifier](t); ok {
+		spec := value.(ParamSpecifier).Spec()
 		return &spec
 	}
 	return nil
 }
 
 func extractFormat(Type reflect.Type) string {
-	if value, ok := resolveInterfaceInstance[paramWithFormat](Type); ok {
-		return value.(paramWithFormat).Format()
+	if value, ok := resolveInterfaceInstance[Formatter](Type); ok {
+		return value.(Formatter).Format()
 	}
 	return ""
 }
 
+func extractEnum(Type reflect.Type) []any {
+	if value, ok := resolveInterfaceInstance[Enumerator](Type); ok {
+		return value.(Enumerator).Enum()
+	}
+	return nil
+}
+
+func extractJSONSchema(Type reflect.Type) map[string]any {
+	if value, ok := resolveInterfaceInstance[SchemaProvider](Type); ok {
+		return value.(SchemaProvider).JSONSchema()
+	}
+	return nil
+}
+
 func extractIn(t reflect.Type) ParamIn {
-	if value, ok := resolveInterfaceInstance[paramWithIn](t); ok {
-		return value.(paramWithIn).In()
+	if value, ok := resolveInterfaceInstance[Locator](t); ok {
+		return value.(Locator).In()
 	}
 
 	return ParamUndefined
 }
+
+func extractDefault(t reflect.Type) (string, bool) {
+	if value, ok := resolveInterfaceInstance[Defaulter](t); ok {
+		return value.(Defaulter).Default(), true
+	}
+
+	return "", false
+}
+
+func extractStyle(t reflect.Type) ParamStyle {
+	if value, ok := resolveInterfaceInstance[Styler](t); ok {
+		return value.(Styler).Style()
+	}
+
+	return StyleUndefined
+}
+
+func extractExplode(t reflect.Type) (bool, bool) {
+	if value, ok := resolveInterfaceInstance[Exploder](t); ok {
+		return value.(Exploder).Explode(), true
+	}
+
+	return false, false
+}
+
+// implementsParser reports if values of type can be parsed from text
+func implementsParser(t reflect.Type) bool {
+	ptr := reflect.PointerTo(t)
+
+	return ptr.Implements(getInterface[ParamParser]()) ||
+		ptr.Implements(getInterface[encoding.TextUnmarshaler]())
+}
@@ -0,0 +1,63 @@
+package goapi_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ goapi.Formatter = goapi.Email("")
+	_ goapi.Formatter = goapi.Datetime("")
+)
+
+type Money int64
+
+func (Money) JSONSchema() map[string]any {
+	return map[string]any{"type": "string", "pattern": `^\d+\.\d{2}$`}
+}
+
+type Currency string
+
+func (Currency) Default() string {
+	return "EUR"
+}
+
+type Invoice struct {
+	Total    Money    `json:"total"`
+	Currency Currency `json:"currency"`
+}
+
+func TestCustomizationInterfaces(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	var invoice Invoice
+
+	handle := appRouter.Post("/invoices", func(i Invoice) goapi.APIError {
+		invoice = i
+		return nil
+	}, goapi.RouteSpec{})
+
+	index := slices.IndexFunc(api.Schemas, func(schema goapi.Schema) bool { return schema.Name == "Invoice" })
+
+	if !assert.NotEqual(t, -1, index, "Invoice schema not registered") {
+		return
+	}
+
+	schema := api.Schemas[index]
+
+	assert.Equal(t, goapi.JsonType("string"), schema.Properties[0].Meta.Type)
+	assert.Equal(t, `^\d+\.\d{2}$`, schema.Properties[0].Meta.Custom["pattern"])
+	assert.Equal(t, `"EUR"`, schema.Properties[1].Meta.Rest["default"])
+
+	recorder := httptest.NewRecorder()
+	handle(recorder, httptest.NewRequest("POST", "/invoices", bytes.NewReader([]byte(`{"total":10}`))), httprouter.Params{})
+
+	assert.Equal(t, Currency("EUR"), invoice.Currency, "Default of field type not applied")
+}
@@ -0,0 +1,106 @@
+// Package testapi is api used by tests of generated code, its types are importable by generated clients.
+package testapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+)
+
+type Calculation struct {
+	Left  int `json:"left"`
+	Right int `json:"right"`
+}
+
+type Result struct {
+	Result int `json:"result"`
+}
+
+type UserID int64
+
+func (UserID) Spec() goapi.Spec {
+	return goapi.Spec{Name: "id", Required: true}
+}
+
+type Fields []string
+
+func (Fields) Spec() goapi.Spec {
+	return goapi.Spec{Name: "fields"}
+}
+
+type Verbose bool
+
+func (Verbose) Spec() goapi.Spec {
+	return goapi.Spec{Name: "verbose"}
+}
+
+type ItemIndex int
+
+func (ItemIndex) Spec() goapi.Spec {
+	return goapi.Spec{Name: "idx", Required: true}
+}
+
+type Limit int
+
+func (Limit) Spec() goapi.Spec {
+	return goapi.Spec{Name: "limit"}
+}
+
+func (Limit) Default() string {
+	return "20"
+}
+
+type Item struct {
+	UserID int64 `json:"userId"`
+	Index  int   `json:"index"`
+	Limit  int   `json:"limit"`
+}
+
+type User struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name"`
+	Email  string   `json:"email"`
+	Fields []string `json:"fields"`
+}
+
+type UpdateUserInput struct {
+	ID   int64 `path:"id"`
+	User User
+	R    goapi.Response
+}
+
+func Calculate(calc Calculation) (Result, goapi.APIError) {
+	if calc.Left < 0 || calc.Right < 0 {
+		return Result{}, goapi.NewAPIError(http.StatusBadRequest, "left and right must be positive", nil)
+	}
+
+	return Result{Result: calc.Left + calc.Right}, nil
+}
+
+func GetUser(id UserID, fields Fields, verbose Verbose) (User, goapi.APIError) {
+	return User{ID: int64(id), Name: "john", Fields: fields}, nil
+}
+
+func GetItem(id UserID, idx ItemIndex, limit Limit) (Item, goapi.APIError) {
+	return Item{UserID: int64(id), Index: int(idx), Limit: int(limit)}, nil
+}
+
+// New returns api of users and calculations
+func New() *goapi.API {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{Title: "Users"})
+	appRouter := api.Router()
+
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{})
+	appRouter.Get("/users/:id", GetUser, goapi.RouteSpec{OperationId: "find-user"})
+	// name of id parameter is prefix of idx parameter preceding it in path
+	appRouter.Get("/items/:idx/users/:id", GetItem, goapi.RouteSpec{})
+	// closures have no name, operation is named after method and path
+	goapi.Put(&appRouter, "/users/:id", func(ctx context.Context, in UpdateUserInput) (User, error) {
+		in.User.ID = in.ID
+		return in.User, nil
+	}, goapi.RouteSpec{})
+
+	return &api
+}
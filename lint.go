@@ -0,0 +1,59 @@
+package goapi
+
+import "fmt"
+
+// LintIssue is documentation problem of route
+type LintIssue struct {
+	Path    string
+	Method  Method
+	Rule    string
+	Message string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s %s: %s (%s)", i.Method, i.Path, i.Message, i.Rule)
+}
+
+// Lint checks documentation of registered routes: summaries, operation ids, parameter descriptions and tags
+func (api *API) Lint() []LintIssue {
+	var issues []LintIssue
+
+	operationIds := map[string]bool{}
+
+	for _, entry := range api.Endpoints {
+		for _, method := range entry.Methods {
+			report := func(rule string, format string, args ...any) {
+				issues = append(issues, LintIssue{
+					Path:    entry.Path,
+					Method:  method.Method,
+					Rule:    rule,
+					Message: fmt.Sprintf(format, args...),
+				})
+			}
+
+			if method.Summary == "" {
+				report("missing-summary", "missing summary")
+			}
+
+			if method.OperationId == "" {
+				report("missing-operation-id", "missing operationId")
+			} else if operationIds[method.OperationId] {
+				report("duplicate-operation-id", "duplicate operationId (%s)", method.OperationId)
+			}
+
+			operationIds[method.OperationId] = true
+
+			for _, param := range method.Parameters {
+				if param.Description == "" {
+					report("undocumented-parameter", "parameter (%s) has no description", param.Name)
+				}
+			}
+
+			if len(method.Tags) == 0 {
+				report("untagged-route", "route has no tags")
+			}
+		}
+	}
+
+	return issues
+}
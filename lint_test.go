@@ -0,0 +1,31 @@
+package goapi_test
+
+import (
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{Summary: "Calculate", OperationId: "calculate", Tags: []string{"math"}})
+	appRouter.Get("/users/:id", GetUser, goapi.RouteSpec{OperationId: "calculate"})
+
+	rules := map[string]int{}
+
+	for _, issue := range api.Lint() {
+		assert.Equal(t, "/users/:id", issue.Path)
+		rules[issue.Rule]++
+	}
+
+	assert.Equal(t, map[string]int{
+		"missing-summary":        1,
+		"duplicate-operation-id": 1,
+		"undocumented-parameter": 3,
+		"untagged-route":         1,
+	}, rules)
+}
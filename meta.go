@@ -1,6 +1,13 @@
 package goapi
 
-import "reflect"
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
 
 type JsonType string
 
@@ -15,8 +22,16 @@ const (
 )
 
 type Meta struct {
-	Type JsonType
-	Rest map[string]string
+	Type                 JsonType
+	Rest                 map[string]string
+	Items                *Meta
+	Properties           []Property
+	AdditionalProperties *Meta
+	// Custom is json schema provided by SchemaProvider, rendered instead of generated parts
+	Custom map[string]any
+
+	// named enum type, rendered as reference when enum components are enabled
+	enumType reflect.Type
 }
 
 func BuildTypeMeta(jsonType JsonType, t reflect.Type) Meta {
@@ -29,6 +44,14 @@ func BuildTypeMeta(jsonType JsonType, t reflect.Type) Meta {
 		meta.Rest["format"] = format
 	}
 
+	if enum, err := enumOf(t); err == nil && enum != nil {
+		meta.Rest["enum"] = formatEnum(enum)
+
+		if t.Name() != "" {
+			meta.enumType = t
+		}
+	}
+
 	return meta
 }
 
@@ -41,3 +64,99 @@ func BuildFieldMeta(jsonType JsonType, f reflect.StructField) Meta {
 
 	return meta
 }
+
+// renderMeta renders meta as yaml lines, each one prefixed by new line and indentation
+func renderMeta(meta Meta, indent int, enumRefs bool) string {
+	var b strings.Builder
+
+	writeMeta(&b, meta, strings.Repeat(" ", indent), enumRefs)
+
+	return b.String()
+}
+
+func writeMeta(b *strings.Builder, meta Meta, pad string, enumRefs bool) {
+	if enumRefs && meta.enumType != nil {
+		fmt.Fprintf(b, "\n%s$ref: '#/components/schemas/%s'", pad, meta.enumType.Name())
+		if value, has := meta.Rest["default"]; has {
+			fmt.Fprintf(b, "\n%sdefault: %s", pad, value)
+		}
+		return
+	}
+
+	if meta.Custom != nil {
+		for _, key := range slices.Sorted(maps.Keys(meta.Custom)) {
+			if _, has := meta.Rest[key]; has {
+				continue
+			}
+			// json is valid yaml flow value
+			bytes, err := json.Marshal(meta.Custom[key])
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(b, "\n%s%s: %s", pad, key, bytes)
+		}
+		for _, key := range slices.Sorted(maps.Keys(meta.Rest)) {
+			fmt.Fprintf(b, "\n%s%s: %s", pad, key, meta.Rest[key])
+		}
+		return
+	}
+
+	fmt.Fprintf(b, "\n%stype: %s", pad, meta.Type)
+
+	for _, key := range slices.Sorted(maps.Keys(meta.Rest)) {
+		fmt.Fprintf(b, "\n%s%s: %s", pad, key, meta.Rest[key])
+	}
+
+	if meta.Items != nil {
+		fmt.Fprintf(b, "\n%sitems:", pad)
+		writeMeta(b, *meta.Items, pad+"  ", enumRefs)
+	}
+
+	if len(meta.Properties) > 0 {
+		fmt.Fprintf(b, "\n%sproperties:", pad)
+		for _, prop := range meta.Properties {
+			fmt.Fprintf(b, "\n%s  %s:", pad, prop.Name)
+			writeMeta(b, prop.Meta, pad+"    ", enumRefs)
+		}
+	}
+
+	if meta.AdditionalProperties != nil {
+		fmt.Fprintf(b, "\n%sadditionalProperties:", pad)
+		writeMeta(b, *meta.AdditionalProperties, pad+"  ", enumRefs)
+	}
+}
+
+// collectEnums returns named enum types referenced by meta
+func collectEnums(meta Meta, out []reflect.Type) []reflect.Type {
+	if meta.enumType != nil && !slices.Contains(out, meta.enumType) {
+		out = append(out, meta.enumType)
+	}
+
+	if meta.Items != nil {
+		out = collectEnums(*meta.Items, out)
+	}
+
+	for _, prop := range meta.Properties {
+		out = collectEnums(prop.Meta, out)
+	}
+
+	if meta.AdditionalProperties != nil {
+		out = collectEnums(*meta.AdditionalProperties, out)
+	}
+
+	return out
+}
+
+// customMeta builds meta of type described by SchemaProvider
+func customMeta(schema map[string]any) Meta {
+	meta := Meta{
+		Rest:   make(map[string]string),
+		Custom: schema,
+	}
+
+	if jsonType, ok := schema["type"].(string); ok {
+		meta.Type = JsonType(jsonType)
+	}
+
+	return meta
+}
@@ -0,0 +1,268 @@
+package goapi
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// default buckets of request duration in seconds
+var defaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// default buckets of response size in bytes
+var defaultSizeBuckets = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+
+var statusClasses = [...]string{"2xx", "3xx", "4xx", "5xx"}
+
+// MetricsOptions configures metrics, zero values use defaults
+type MetricsOptions struct {
+	// Path of exposition route relative to router, defaults to /metrics
+	Path string
+	// DurationBuckets are upper bounds of request duration histogram in seconds
+	DurationBuckets []float64
+	// SizeBuckets are upper bounds of response size histogram in bytes
+	SizeBuckets []float64
+}
+
+// routeKey identifies registered route
+type routeKey struct {
+	method Method
+	path   string
+}
+
+type histogram struct {
+	bounds  []float64
+	buckets []atomic.Uint64
+	count   atomic.Uint64
+	sum     atomic.Uint64 // float64 bits
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds:  bounds,
+		buckets: make([]atomic.Uint64, len(bounds)),
+	}
+}
+
+func (h *histogram) observe(value float64) {
+	for i, bound := range h.bounds {
+		if value <= bound {
+			h.buckets[i].Add(1)
+			break
+		}
+	}
+
+	h.count.Add(1)
+
+	for {
+		old := h.sum.Load()
+		if h.sum.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+value)) {
+			return
+		}
+	}
+}
+
+// routeMetrics are series of route, one per status class
+type routeMetrics struct {
+	method      string
+	path        string
+	operationId string
+	inFlight    atomic.Int64
+	requests    [len(statusClasses)]atomic.Uint64
+	durations   [len(statusClasses)]*histogram
+	sizes       [len(statusClasses)]*histogram
+}
+
+// metrics are series of all routes, pre-registered so routes without requests are exposed
+type metrics struct {
+	opts   MetricsOptions
+	routes map[routeKey]*routeMetrics
+	order  []*routeMetrics
+}
+
+func (m *metrics) register(path string, endpointMethod *EndpointMethod) {
+	key := routeKey{endpointMethod.Method, path}
+
+	if _, has := m.routes[key]; has {
+		return
+	}
+
+	route := &routeMetrics{
+		method:      strings.ToUpper(string(endpointMethod.Method)),
+		path:        path,
+		operationId: endpointMethod.OperationId,
+	}
+
+	for i := range statusClasses {
+		route.durations[i] = newHistogram(m.opts.DurationBuckets)
+		route.sizes[i] = newHistogram(m.opts.SizeBuckets)
+	}
+
+	m.routes[key] = route
+	m.order = append(m.order, route)
+}
+
+// Metrics records requests of all routes and exposes them in prometheus text format on router
+func (api *API) Metrics(router *Router, opts MetricsOptions) {
+	if opts.Path == "" {
+		opts.Path = "/metrics"
+	}
+
+	if opts.DurationBuckets == nil {
+		opts.DurationBuckets = defaultDurationBuckets
+	}
+
+	if opts.SizeBuckets == nil {
+		opts.SizeBuckets = defaultSizeBuckets
+	}
+
+	m := &metrics{
+		opts:   opts,
+		routes: make(map[routeKey]*routeMetrics),
+	}
+
+	for _, entry := range api.Endpoints {
+		for index := range entry.Methods {
+			m.register(entry.Path, &entry.Methods[index])
+		}
+	}
+
+	api.metrics = m
+
+	path := joinPrefix(router.prefix, opts.Path)
+
+	if err := handleRoute(api.router, http.MethodGet, path, m.serve); err != nil {
+		api.addRegistrationError(path, MethodGet, "Metrics", err)
+	}
+}
+
+func statusClass(status int) int {
+	return min(max(status/100, 2), 5) - 2
+}
+
+// withMetrics records requests of route, when metrics are enabled
+func withMetrics(api *API, path string, endpointMethod *EndpointMethod, handle httprouter.Handle) httprouter.Handle {
+	if api.metrics != nil {
+		api.metrics.register(path, endpointMethod)
+	}
+
+	key := routeKey{endpointMethod.Method, path}
+
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		if api.metrics == nil {
+			handle(w, req, params)
+			return
+		}
+
+		route := api.metrics.routes[key]
+
+		route.inFlight.Add(1)
+		defer route.inFlight.Add(-1)
+
+		start := time.Now()
+		recorder := &statusWriter{ResponseWriter: w}
+
+		handle(recorder, req, params)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		class := statusClass(status)
+
+		route.requests[class].Add(1)
+		route.durations[class].observe(time.Since(start).Seconds())
+		route.sizes[class].observe(float64(recorder.bytes))
+	}
+}
+
+func (m *metrics) serve(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	m.write(buf)
+
+	w.Write(buf.Bytes())
+}
+
+// write writes all series in prometheus text exposition format
+func (m *metrics) write(out io.Writer) {
+	fmt.Fprintf(out, "# HELP goapi_http_requests_total Total number of handled requests.\n")
+	fmt.Fprintf(out, "# TYPE goapi_http_requests_total counter\n")
+
+	for _, route := range m.order {
+		for class, name := range statusClasses {
+			fmt.Fprintf(out, "goapi_http_requests_total{%s} %d\n", route.labels(name), route.requests[class].Load())
+		}
+	}
+
+	fmt.Fprintf(out, "# HELP goapi_http_requests_in_flight Number of requests being handled.\n")
+	fmt.Fprintf(out, "# TYPE goapi_http_requests_in_flight gauge\n")
+
+	for _, route := range m.order {
+		fmt.Fprintf(out, "goapi_http_requests_in_flight{%s} %d\n", route.labels(""), route.inFlight.Load())
+	}
+
+	writeHistograms(out, m.order, "goapi_http_request_duration_seconds", "Duration of requests in seconds.",
+		func(route *routeMetrics, class int) *histogram { return route.durations[class] })
+
+	writeHistograms(out, m.order, "goapi_http_response_size_bytes", "Size of response bodies in bytes.",
+		func(route *routeMetrics, class int) *histogram { return route.sizes[class] })
+}
+
+func writeHistograms(out io.Writer, routes []*routeMetrics, name string, help string, of func(*routeMetrics, int) *histogram) {
+	fmt.Fprintf(out, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(out, "# TYPE %s histogram\n", name)
+
+	for _, route := range routes {
+		for class, className := range statusClasses {
+			h := of(route, class)
+			labels := route.labels(className)
+
+			// count is loaded first, so buckets never exceed it
+			count := h.count.Load()
+			var cumulative uint64
+
+			for i, bound := range h.bounds {
+				cumulative += h.buckets[i].Load()
+				fmt.Fprintf(out, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatFloat(bound), min(cumulative, count))
+			}
+
+			fmt.Fprintf(out, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, count)
+			fmt.Fprintf(out, "%s_sum{%s} %s\n", name, labels, formatFloat(math.Float64frombits(h.sum.Load())))
+			fmt.Fprintf(out, "%s_count{%s} %d\n", name, labels, count)
+		}
+	}
+}
+
+// labels returns label pairs of route, status class is omitted when empty
+func (route *routeMetrics) labels(class string) string {
+	labels := fmt.Sprintf(`method="%s",route="%s",operation_id="%s"`,
+		escapeLabel(route.method), escapeLabel(route.path), escapeLabel(route.operationId))
+
+	if class != "" {
+		labels += `,status_class="` + class + `"`
+	}
+
+	return labels
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
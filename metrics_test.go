@@ -0,0 +1,47 @@
+package goapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{OperationId: "calculate"})
+	api.Metrics(&appRouter, goapi.MetricsOptions{Path: "/internal/metrics"})
+
+	// routes registered after metrics are recorded too
+	appRouter.Get("/users/:id", GetUser, goapi.RouteSpec{})
+
+	serve := func(method string, path string, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		api.Handler().ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return recorder
+	}
+
+	serve("POST", "/calculate", `{"left": 1, "right": 2}`)
+	serve("POST", "/calculate", `{"left": 1`)
+
+	recorder := serve("GET", "/internal/metrics", "")
+	assert.Exactly(t, http.StatusOK, recorder.Code)
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain"))
+
+	body := recorder.Body.String()
+
+	assert.Contains(t, body, `goapi_http_requests_total{method="POST",route="/calculate",operation_id="calculate",status_class="2xx"} 1`)
+	assert.Contains(t, body, `goapi_http_requests_total{method="POST",route="/calculate",operation_id="calculate",status_class="4xx"} 1`)
+	assert.Contains(t, body, `goapi_http_request_duration_seconds_count{method="POST",route="/calculate",operation_id="calculate",status_class="2xx"} 1`)
+	assert.Contains(t, body, `goapi_http_response_size_bytes_bucket{method="POST",route="/calculate",operation_id="calculate",status_class="2xx",le="+Inf"} 1`)
+	assert.Contains(t, body, `goapi_http_requests_in_flight{method="POST",route="/calculate",operation_id="calculate"} 0`)
+
+	// series are pre-registered by route template
+	assert.Contains(t, body, `goapi_http_requests_total{method="GET",route="/users/:id",operation_id="",status_class="5xx"} 0`)
+}
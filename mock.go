@@ -0,0 +1,268 @@
+package goapi
+
+import (
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// MockOverride replaces generated response of operation, zero values keep defaults
+type MockOverride struct {
+	// Status of response, defaults to documented success status
+	Status int
+	// Delay before response is sent
+	Delay time.Duration
+	// Fixture is file sent as json body instead of generated one
+	Fixture string
+}
+
+// MockServer serves operations of document with example or random responses conforming to their schemas.
+// Requests are validated against declared parameters and bodies, violations are answered with 400.
+type MockServer struct {
+	document  *SpecDocument
+	router    *httprouter.Router
+	fallback  []mockRoute
+	mu        sync.Mutex
+	rand      *rand.Rand
+	overrides map[string]MockOverride
+}
+
+// mockRoute serves path rejected by router, e.g. /users/{id} next to /users/me
+type mockRoute struct {
+	method  string
+	pattern *regexp.Regexp
+	names   []string
+	handle  httprouter.Handle
+}
+
+// NewMockServer returns mock server of operations registered by api
+func NewMockServer(api *API) (*MockServer, error) {
+	document, err := api.SpecDocument()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSpecMockServer(document), nil
+}
+
+// NewSpecMockServer returns mock server of document, paths may use {name} or :name parameters.
+// Paths conflicting in router, like /users/me and /users/{id}, are served by matching them in order.
+func NewSpecMockServer(document *SpecDocument) *MockServer {
+	m := &MockServer{
+		document:  document,
+		router:    httprouter.New(),
+		rand:      rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)),
+		overrides: make(map[string]MockOverride),
+	}
+
+	// requests not matched by router are matched by fallback, whatever their method
+	m.router.HandleMethodNotAllowed = false
+	m.router.NotFound = http.HandlerFunc(m.serveFallback)
+
+	for _, path := range slices.Sorted(maps.Keys(document.Paths)) {
+		routePath := pathTemplate.ReplaceAllString(path, ":$1")
+
+		for _, method := range slices.Sorted(maps.Keys(document.Paths[path])) {
+			handle := m.handle(routePath, method, document.Paths[path][method])
+
+			if err := handleRoute(m.router, strings.ToUpper(method), routePath, handle); err != nil {
+				m.fallback = append(m.fallback, newMockRoute(strings.ToUpper(method), routePath, handle))
+			}
+		}
+	}
+
+	return m
+}
+
+// newMockRoute matches route path with :name and *name parameters by regular expression
+func newMockRoute(method, routePath string, handle httprouter.Handle) mockRoute {
+	route := mockRoute{method: method, handle: handle}
+	segments := strings.Split(routePath, "/")
+
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			route.names = append(route.names, segment[1:])
+			segments[i] = "([^/]+)"
+		case strings.HasPrefix(segment, "*"):
+			route.names = append(route.names, segment[1:])
+			segments[i] = "(.*)"
+		default:
+			segments[i] = regexp.QuoteMeta(segment)
+		}
+	}
+
+	route.pattern = regexp.MustCompile("^" + strings.Join(segments, "/") + "$")
+
+	return route
+}
+
+func (m *MockServer) serveFallback(w http.ResponseWriter, req *http.Request) {
+	for _, route := range m.fallback {
+		match := route.pattern.FindStringSubmatch(req.URL.Path)
+
+		if route.method != req.Method || match == nil {
+			continue
+		}
+
+		params := make(httprouter.Params, 0, len(route.names))
+		for i, name := range route.names {
+			params = append(params, httprouter.Param{Key: name, Value: match[i+1]})
+		}
+
+		route.handle(w, req, params)
+		return
+	}
+
+	http.NotFound(w, req)
+}
+
+// Override replaces response of operation, named by operationId or method and path,
+// e.g. "GET /users/{id}" or "GET /users/:id"
+func (m *MockServer) Override(operation string, override MockOverride) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if method, path, found := strings.Cut(operation, " "); found {
+		operation = strings.ToUpper(method) + " " + pathTemplate.ReplaceAllString(path, ":$1")
+	}
+
+	m.overrides[operation] = override
+}
+
+// Seed makes generated responses reproducible
+func (m *MockServer) Seed(seed uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rand = rand.New(rand.NewPCG(seed, 0))
+}
+
+func (m *MockServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	m.router.ServeHTTP(w, req)
+}
+
+// override returns override of operation, keys of method and path are normalized to route path
+func (m *MockServer) override(routePath, method string, operation *SpecOperation) MockOverride {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if override, has := m.overrides[operation.OperationId]; has && operation.OperationId != "" {
+		return override
+	}
+
+	return m.overrides[strings.ToUpper(method)+" "+routePath]
+}
+
+func (m *MockServer) handle(routePath, method string, operation *SpecOperation) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		violations, err := m.document.validateRequest(operation, req, params, false)
+
+		if err != nil {
+			writeJSON(w, bodyErrorStatus(err), nil, DefaultErrorType{Detail: bodyErrorDetail(err)})
+			return
+		}
+
+		if len(violations) > 0 {
+			writeJSON(w, http.StatusBadRequest, nil, DefaultErrorType{Detail: strings.Join(violations, "; ")})
+			return
+		}
+
+		override := m.override(routePath, method, operation)
+
+		if override.Delay > 0 {
+			select {
+			case <-time.After(override.Delay):
+			case <-req.Context().Done():
+				return
+			}
+		}
+
+		status, response := successResponse(operation)
+
+		if override.Status != 0 {
+			status = override.Status
+			response = matchResponse(operation, status)
+		}
+
+		if override.Fixture != "" {
+			data, err := os.ReadFile(override.Fixture)
+
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, nil, DefaultErrorType{Detail: err.Error()})
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write(data)
+			return
+		}
+
+		if response == nil {
+			w.WriteHeader(status)
+			return
+		}
+
+		media, has := response.Content["application/json"]
+		if !has {
+			w.WriteHeader(status)
+			return
+		}
+
+		body := media.Example
+
+		if body == nil {
+			if schema := m.document.resolve(media.Schema); schema != nil && schema.Example != nil {
+				body = schema.Example
+			} else {
+				m.mu.Lock()
+				body = m.document.RandomValue(media.Schema, m.rand)
+				m.mu.Unlock()
+			}
+		}
+
+		writeJSON(w, status, http.Header{"Content-Type": {"application/json"}}, body)
+	}
+}
+
+// successResponse returns first documented 2xx status and its response, 200 when there is none
+func successResponse(operation *SpecOperation) (int, *SpecResponse) {
+	for _, status := range slices.Sorted(maps.Keys(operation.Responses)) {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+
+		code, err := strconv.Atoi(status)
+		if err != nil {
+			code = http.StatusOK
+		}
+
+		return code, operation.Responses[status]
+	}
+
+	return http.StatusOK, nil
+}
+
+// matchResponse returns response documented for status, by status, its class or default
+func matchResponse(operation *SpecOperation, status int) *SpecResponse {
+	code := strconv.Itoa(status)
+
+	for _, key := range []string{code, code[:1] + "XX", "default"} {
+		if response, has := operation.Responses[key]; has {
+			return response
+		}
+	}
+
+	return nil
+}
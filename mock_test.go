@@ -0,0 +1,171 @@
+package goapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const mockSpec = `
+openapi: 3.1.0
+info: {title: pets, version: "1"}
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+        - {name: verbose, in: query, schema: {type: boolean}}
+      responses:
+        "200":
+          description: pet
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+        "404":
+          description: missing
+  /pets:
+    get:
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+              example: [{id: 1, name: rex, kind: dog}]
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name, kind]
+      properties:
+        id: {type: integer}
+        name: {type: string}
+        kind: {type: string, enum: [dog, cat]}
+`
+
+func serveMock(handler http.Handler, method, target string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+	return recorder
+}
+
+func TestMockServerSpec(t *testing.T) {
+	document, err := goapi.ParseSpec([]byte(mockSpec))
+	assert.NoError(t, err)
+
+	server := goapi.NewSpecMockServer(document)
+	server.Seed(1)
+
+	for range 20 {
+		recorder := serveMock(server, "GET", "/pets/7?verbose=true")
+		assert.Exactly(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, document.ValidateResponse("/pets/{id}", "get", recorder.Code, recorder.Body.Bytes()))
+	}
+
+	recorder := serveMock(server, "GET", "/pets")
+	assert.Exactly(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `[{"id": 1, "name": "rex", "kind": "dog"}]`, recorder.Body.String(), "Example is served")
+
+	recorder = serveMock(server, "GET", "/pets/seven?verbose=maybe")
+	assert.Exactly(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "parameter id: expected integer, got string")
+	assert.Contains(t, recorder.Body.String(), "parameter verbose: expected boolean, got string")
+}
+
+func TestMockServerOverride(t *testing.T) {
+	document, err := goapi.ParseSpec([]byte(mockSpec))
+	assert.NoError(t, err)
+
+	fixture := filepath.Join(t.TempDir(), "pet.json")
+	assert.NoError(t, os.WriteFile(fixture, []byte(`{"id": 2, "name": "tom", "kind": "cat"}`), 0o644))
+
+	server := goapi.NewSpecMockServer(document)
+
+	server.Override("getPet", goapi.MockOverride{Fixture: fixture, Delay: 20 * time.Millisecond})
+
+	start := time.Now()
+	recorder := serveMock(server, "GET", "/pets/2")
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Exactly(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"id": 2, "name": "tom", "kind": "cat"}`, recorder.Body.String())
+
+	server.Override("GET /pets", goapi.MockOverride{Status: http.StatusServiceUnavailable})
+
+	recorder = serveMock(server, "GET", "/pets")
+	assert.Exactly(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+}
+
+func TestMockServerAPI(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	appRouter.Post("/transfers", MakeTransfer, goapi.RouteSpec{OperationId: "makeTransfer"})
+
+	server, err := goapi.NewMockServer(&api)
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest("POST", "/transfers", strings.NewReader(`{"amount": 10}`)))
+	assert.Exactly(t, http.StatusOK, recorder.Code)
+	document, err := api.SpecDocument()
+	assert.NoError(t, err)
+	assert.Empty(t, document.ValidateResponse("/transfers", "post", recorder.Code, recorder.Body.Bytes()))
+
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest("POST", "/transfers", strings.NewReader(`{"note": "x"}`)))
+	assert.Exactly(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "request body .amount: missing required field")
+}
+
+func TestMockServerConflictingPaths(t *testing.T) {
+	document, err := goapi.ParseSpec([]byte(`
+paths:
+  /users/me:
+    get:
+      responses:
+        "200":
+          description: me
+          content:
+            application/json:
+              example: {name: me}
+  /users/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        "200":
+          description: user
+          content:
+            application/json:
+              example: {name: other}
+`))
+	assert.NoError(t, err)
+
+	server := goapi.NewSpecMockServer(document)
+
+	recorder := serveMock(server, "GET", "/users/me")
+	assert.JSONEq(t, `{"name": "me"}`, recorder.Body.String())
+
+	recorder = serveMock(server, "GET", "/users/5")
+	assert.Exactly(t, http.StatusOK, recorder.Code, "Conflicting path not served")
+	assert.JSONEq(t, `{"name": "other"}`, recorder.Body.String())
+
+	recorder = serveMock(server, "GET", "/users/five")
+	assert.Exactly(t, http.StatusBadRequest, recorder.Code, "Parameters of conflicting path not validated")
+
+	assert.Exactly(t, http.StatusNotFound, serveMock(server, "POST", "/users/5").Code)
+
+	server.Override("get /users/{id}", goapi.MockOverride{Status: http.StatusNotFound})
+
+	assert.Exactly(t, http.StatusNotFound, serveMock(server, "GET", "/users/5").Code, "Override by document path not applied")
+	assert.Exactly(t, http.StatusOK, serveMock(server, "GET", "/users/me").Code)
+}
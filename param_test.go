@@ -0,0 +1,293 @@
+package goapi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type TagList []string
+
+func (TagList) Spec() goapi.Spec {
+	return goapi.Spec{Name: "tag"}
+}
+
+type CommaTagList []string
+
+func (CommaTagList) Spec() goapi.Spec {
+	return goapi.Spec{Name: "tag"}
+}
+
+func (CommaTagList) Explode() bool {
+	return false
+}
+
+type PipeIDs []int
+
+func (PipeIDs) Spec() goapi.Spec {
+	return goapi.Spec{Name: "ids"}
+}
+
+func (PipeIDs) Style() goapi.ParamStyle {
+	return goapi.StylePipeDelimited
+}
+
+func (PipeIDs) Explode() bool {
+	return false
+}
+
+type IssueFilter struct {
+	Status string `json:"status"`
+	Limit  int    `json:"limit"`
+}
+
+func (IssueFilter) Spec() goapi.Spec {
+	return goapi.Spec{Name: "filter"}
+}
+
+func (IssueFilter) Style() goapi.ParamStyle {
+	return goapi.StyleDeepObject
+}
+
+type Labels map[string]string
+
+func (Labels) Spec() goapi.Spec {
+	return goapi.Spec{Name: "labels"}
+}
+
+type Echo struct {
+	Tags   []string          `json:"tags"`
+	IDs    []int             `json:"ids"`
+	Filter IssueFilter       `json:"-"`
+	Labels map[string]string `json:"labels"`
+}
+
+func request(endpoint goapi.Endpoint, method goapi.Method, url string, body string) *httptest.ResponseRecorder {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	handle := appRouter.Route(method, "/issues", endpoint, goapi.RouteSpec{})
+
+	recorder := httptest.NewRecorder()
+	handle(recorder, httptest.NewRequest(string(method), url, strings.NewReader(body)), httprouter.Params{})
+
+	return recorder
+}
+
+func serve(t *testing.T, endpoint goapi.Endpoint, url string) Echo {
+	recorder := request(endpoint, goapi.MethodGet, url, "")
+
+	var echo Echo
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &echo), "Unable to unmarshal echo")
+
+	return echo
+}
+
+func TestExplodedArrayParameter(t *testing.T) {
+	echo := serve(t, func(tags TagList) (Echo, goapi.APIError) {
+		return Echo{Tags: tags}, nil
+	}, "/issues?tag=a&tag=b")
+
+	assert.Equal(t, []string{"a", "b"}, echo.Tags)
+}
+
+func TestDelimitedArrayParameter(t *testing.T) {
+	echo := serve(t, func(tags CommaTagList, ids PipeIDs) (Echo, goapi.APIError) {
+		return Echo{Tags: tags, IDs: ids}, nil
+	}, "/issues?tag=a,b&ids=1|2|3")
+
+	assert.Equal(t, []string{"a", "b"}, echo.Tags)
+	assert.Equal(t, []int{1, 2, 3}, echo.IDs)
+}
+
+func TestDeepObjectParameter(t *testing.T) {
+	var filter IssueFilter
+
+	echo := serve(t, func(f IssueFilter, labels Labels) (Echo, goapi.APIError) {
+		filter = f
+		return Echo{Labels: labels}, nil
+	}, "/issues?filter[status]=open&filter[limit]=5&labels[team]=core")
+
+	assert.Equal(t, IssueFilter{Status: "open", Limit: 5}, filter)
+	assert.Equal(t, map[string]string{"team": "core"}, echo.Labels)
+}
+
+type Since struct {
+	time.Time
+}
+
+func (Since) Spec() goapi.Spec {
+	return goapi.Spec{Name: "since"}
+}
+
+type Level int
+
+func (Level) Spec() goapi.Spec {
+	return goapi.Spec{Name: "level"}
+}
+
+func (l *Level) ParseParam(value string) error {
+	switch value {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %q", value)
+	}
+	return nil
+}
+
+func TestCustomParameterParsers(t *testing.T) {
+	var (
+		since Since
+		level Level
+		at    goapi.Datetime
+	)
+
+	serve(t, func(s Since, l Level, d goapi.Datetime) (Echo, goapi.APIError) {
+		since, level, at = s, l, d
+		return Echo{}, nil
+	}, "/issues?since=2024-01-02T03:04:05Z&level=high&Datetime=2024-05-06T07:08:09Z")
+
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), since.Time)
+	assert.Equal(t, Level(2), level)
+	assert.Equal(t, time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC), at.Time())
+}
+
+type PageLimit int
+
+func (PageLimit) Spec() goapi.Spec {
+	return goapi.Spec{Name: "limit"}
+}
+
+func (PageLimit) Default() string {
+	return "20"
+}
+
+type DefaultTags []string
+
+func (DefaultTags) Spec() goapi.Spec {
+	return goapi.Spec{Name: "tag"}
+}
+
+func (DefaultTags) Default() string {
+	return "open,new"
+}
+
+func TestParameterDefaults(t *testing.T) {
+	var limit PageLimit
+
+	echo := serve(t, func(l PageLimit, tags DefaultTags) (Echo, goapi.APIError) {
+		limit = l
+		return Echo{Tags: tags}, nil
+	}, "/issues")
+
+	assert.Equal(t, PageLimit(20), limit)
+	assert.Equal(t, []string{"open", "new"}, echo.Tags)
+
+	serve(t, func(l PageLimit) (Echo, goapi.APIError) {
+		limit = l
+		return Echo{}, nil
+	}, "/issues?limit=5")
+
+	assert.Equal(t, PageLimit(5), limit)
+}
+
+type IssueStatus string
+
+const (
+	IssueOpen   IssueStatus = "open"
+	IssueClosed IssueStatus = "closed"
+)
+
+func (IssueStatus) Spec() goapi.Spec {
+	return goapi.Spec{Name: "status"}
+}
+
+func (IssueStatus) Enum() []any {
+	return []any{IssueOpen, IssueClosed}
+}
+
+type Issue struct {
+	Status   IssueStatus `json:"status"`
+	Priority int         `json:"priority" enum:"1,2,3"`
+}
+
+func TestEnumParameter(t *testing.T) {
+	var status IssueStatus
+
+	endpoint := func(s IssueStatus) (Echo, goapi.APIError) {
+		status = s
+		return Echo{}, nil
+	}
+
+	serve(t, endpoint, "/issues?status=closed")
+
+	assert.Equal(t, IssueClosed, status)
+
+	recorder := request(endpoint, goapi.MethodGet, "/issues?status=unknown", "")
+
+	assert.Exactly(t, http.StatusUnprocessableEntity, recorder.Code, "Out of range value accepted")
+}
+
+func TestEnumBodyFields(t *testing.T) {
+	endpoint := func(issue Issue) (Issue, goapi.APIError) {
+		return issue, nil
+	}
+
+	recorder := request(endpoint, goapi.MethodPost, "/issues", `{"status":"open","priority":2}`)
+
+	assert.Exactly(t, http.StatusOK, recorder.Code)
+
+	recorder = request(endpoint, goapi.MethodPost, "/issues", `{"status":"done","priority":2}`)
+
+	assert.Exactly(t, http.StatusUnprocessableEntity, recorder.Code, "Out of range type enum accepted")
+
+	recorder = request(endpoint, goapi.MethodPost, "/issues", `{"status":"open","priority":7}`)
+
+	assert.Exactly(t, http.StatusUnprocessableEntity, recorder.Code, "Out of range tag enum accepted")
+}
+
+type IssueUpdate struct {
+	Title  string       `json:"title"`
+	Status *IssueStatus `json:"status"`
+	State  IssueStatus  `json:"state" required:"true"`
+}
+
+func TestEnumOmittedBodyFields(t *testing.T) {
+	endpoint := func(issue Issue) (Issue, goapi.APIError) {
+		return issue, nil
+	}
+
+	recorder := request(endpoint, goapi.MethodPost, "/issues", `{}`)
+
+	assert.Exactly(t, http.StatusOK, recorder.Code, "Omitted optional enum field rejected")
+
+	recorder = request(endpoint, goapi.MethodPost, "/issues", `{"status":""}`)
+
+	assert.Exactly(t, http.StatusUnprocessableEntity, recorder.Code, "Sent zero value out of enum accepted")
+
+	update := func(issue IssueUpdate) (Issue, goapi.APIError) {
+		return Issue{}, nil
+	}
+
+	recorder = request(update, goapi.MethodPost, "/issues", `{"title":"a","state":"open"}`)
+
+	assert.Exactly(t, http.StatusOK, recorder.Code, "Nil pointer enum field rejected")
+
+	recorder = request(update, goapi.MethodPost, "/issues", `{"title":"a","status":null,"state":"open"}`)
+
+	assert.Exactly(t, http.StatusOK, recorder.Code, "Null pointer enum field rejected")
+
+	recorder = request(update, goapi.MethodPost, "/issues", `{"title":"a"}`)
+
+	assert.Exactly(t, http.StatusUnprocessableEntity, recorder.Code, "Omitted required enum field accepted")
+}
@@ -0,0 +1,454 @@
+package goapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// valueParser parses raw request values into value of type it was compiled for
+type valueParser func(values []string) (reflect.Value, error)
+
+// deepObjectParser parses query values in form of name[key]=value
+type deepObjectParser func(query url.Values, name string) (reflect.Value, bool, error)
+
+// parseTypedValue parses raw values into Type, used where parser is not compiled ahead
+func parseTypedValue(values []string, Type reflect.Type) (reflect.Value, error) {
+	parse, err := compileParser(Type)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return parse(values)
+}
+
+// compileParser builds parser of Type checking values against its enum, arrays consume all values
+func compileParser(Type reflect.Type) (valueParser, error) {
+	parse, err := compileRawParser(Type)
+
+	if err != nil {
+		return nil, err
+	}
+
+	enum, err := enumOf(Type)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if enum == nil {
+		return parse, nil
+	}
+
+	return func(values []string) (reflect.Value, error) {
+		value, err := parse(values)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return value, checkEnum(value, enum)
+	}, nil
+}
+
+func compileRawParser(Type reflect.Type) (valueParser, error) {
+	ptr := reflect.PointerTo(Type)
+
+	// custom parsers first, ParamParser takes precedence
+	switch {
+	case ptr.Implements(getInterface[ParamParser]()):
+		return func(values []string) (reflect.Value, error) {
+			value := reflect.New(Type)
+			err := value.Interface().(ParamParser).ParseParam(values[0])
+			return value.Elem(), err
+		}, nil
+	case ptr.Implements(getInterface[encoding.TextUnmarshaler]()):
+		return func(values []string) (reflect.Value, error) {
+			value := reflect.New(Type)
+			err := value.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(values[0]))
+			return value.Elem(), err
+		}, nil
+	}
+
+	if Type.Kind() == reflect.Pointer {
+		parse, err := compileParser(Type.Elem())
+
+		if err != nil {
+			return nil, err
+		}
+
+		return func(values []string) (reflect.Value, error) {
+			value, err := parse(values)
+
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			ptr := reflect.New(Type.Elem())
+			ptr.Elem().Set(value)
+
+			return ptr, nil
+		}, nil
+	}
+
+	jt, err := resolveJsonType(Type)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch jt.jsonType {
+	case JsonArray:
+		return compileArrayParser(Type)
+	case JsonObject:
+		return nil, fmt.Errorf("unable to parse object (%s) from text", Type)
+	}
+
+	return compileScalarParser(jt.jsonType, Type), nil
+}
+
+func compileScalarParser(jsonType JsonType, Type reflect.Type) valueParser {
+	switch jsonType {
+	case JsonBoolean:
+		return func(values []string) (reflect.Value, error) {
+			value := reflect.New(Type).Elem()
+
+			switch values[0] {
+			case "true":
+				value.SetBool(true)
+			case "false":
+			default:
+				return reflect.Value{}, fmt.Errorf("invalid boolean value: %s", values[0])
+			}
+
+			return value, nil
+		}
+	case JsonInteger:
+		switch Type.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return func(values []string) (reflect.Value, error) {
+				val, err := strconv.ParseUint(values[0], 10, Type.Bits())
+
+				if err != nil {
+					return reflect.Value{}, fmt.Errorf("invalid integer value: %s", values[0])
+				}
+
+				value := reflect.New(Type).Elem()
+				value.SetUint(val)
+
+				return value, nil
+			}
+		}
+
+		return func(values []string) (reflect.Value, error) {
+			val, err := strconv.ParseInt(values[0], 10, Type.Bits())
+
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("invalid integer value: %s", values[0])
+			}
+
+			value := reflect.New(Type).Elem()
+			value.SetInt(val)
+
+			return value, nil
+		}
+	case JsonNumber:
+		return func(values []string) (reflect.Value, error) {
+			val, err := strconv.ParseFloat(values[0], Type.Bits())
+
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("invalid number value: %s", values[0])
+			}
+
+			value := reflect.New(Type).Elem()
+			value.SetFloat(val)
+
+			return value, nil
+		}
+	}
+
+	// []byte is passed as raw string
+	if Type.Kind() == reflect.Slice {
+		return func(values []string) (reflect.Value, error) {
+			value := reflect.New(Type).Elem()
+			value.SetBytes([]byte(values[0]))
+
+			return value, nil
+		}
+	}
+
+	return func(values []string) (reflect.Value, error) {
+		value := reflect.New(Type).Elem()
+		value.SetString(values[0])
+
+		return value, nil
+	}
+}
+
+func compileArrayParser(Type reflect.Type) (valueParser, error) {
+	parse, err := compileParser(Type.Elem())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(values []string) (reflect.Value, error) {
+		var out reflect.Value
+
+		if Type.Kind() == reflect.Array {
+			if len(values) != Type.Len() {
+				return reflect.Value{}, fmt.Errorf("expected %d values, got %d", Type.Len(), len(values))
+			}
+			out = reflect.New(Type).Elem()
+		} else {
+			out = reflect.MakeSlice(Type, len(values), len(values))
+		}
+
+		for i := range values {
+			value, err := parse(values[i : i+1])
+
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			out.Index(i).Set(value)
+		}
+
+		return out, nil
+	}, nil
+}
+
+func compileDeepObjectParser(Type reflect.Type) (deepObjectParser, error) {
+	type fieldParser struct {
+		index int
+		parse valueParser
+	}
+
+	var (
+		valueParse valueParser
+		fields     map[string]fieldParser
+	)
+
+	if Type.Kind() == reflect.Map {
+		parse, err := compileParser(Type.Elem())
+
+		if err != nil {
+			return nil, err
+		}
+
+		valueParse = parse
+	} else {
+		fields = make(map[string]fieldParser)
+
+		for i := 0; i < Type.NumField(); i++ {
+			field := Type.Field(i)
+
+			name, ok := jsonFieldName(field)
+
+			if !ok {
+				continue
+			}
+
+			parse, err := compileParser(field.Type)
+
+			if err != nil {
+				return nil, err
+			}
+
+			fields[name] = fieldParser{index: i, parse: parse}
+		}
+	}
+
+	return func(query url.Values, name string) (reflect.Value, bool, error) {
+		prefix := name + "["
+		found := false
+
+		var out reflect.Value
+
+		if valueParse != nil {
+			out = reflect.MakeMap(Type)
+		} else {
+			out = reflect.New(Type).Elem()
+		}
+
+		for key, values := range query {
+			if !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, "]") || len(values) == 0 {
+				continue
+			}
+
+			property := key[len(prefix) : len(key)-1]
+
+			if valueParse != nil {
+				value, err := valueParse(values)
+
+				if err != nil {
+					return reflect.Value{}, false, err
+				}
+
+				out.SetMapIndex(reflect.ValueOf(property).Convert(Type.Key()), value)
+			} else {
+				field, ok := fields[property]
+
+				if !ok {
+					continue
+				}
+
+				value, err := field.parse(values)
+
+				if err != nil {
+					return reflect.Value{}, false, err
+				}
+
+				out.Field(field.index).Set(value)
+			}
+
+			found = true
+		}
+
+		return out, found, nil
+	}, nil
+}
+
+// schemaPlan applies defaults and validates enums and required fields of decoded body schema
+type schemaPlan struct {
+	defaults []fieldDefaultPlan
+	enums    []fieldEnumPlan
+	// required are json names of fields tagged required
+	required []string
+}
+
+type fieldDefaultPlan struct {
+	index  int
+	values []string
+	parse  valueParser
+}
+
+type fieldEnumPlan struct {
+	index    int
+	name     string
+	enum     []string
+	required bool
+}
+
+func compileSchemaPlan(Type reflect.Type) (schemaPlan, error) {
+	var plan schemaPlan
+
+	for i := 0; i < Type.NumField(); i++ {
+		field := Type.Field(i)
+
+		name, ok := jsonFieldName(field)
+
+		if !ok {
+			continue
+		}
+
+		if raw, has := fieldDefault(field); has {
+			parse, err := compileParser(field.Type)
+
+			if err != nil {
+				return schemaPlan{}, err
+			}
+
+			values := defaultValues(raw, field.Type, StyleUndefined)
+
+			// defaults are parsed per request, so values are not shared between requests
+			if _, err := parse(values); err != nil {
+				return schemaPlan{}, fmt.Errorf("invalid default of field (%s): %w", field.Name, err)
+			}
+
+			plan.defaults = append(plan.defaults, fieldDefaultPlan{
+				index:  i,
+				values: values,
+				parse:  parse,
+			})
+		}
+
+		required, _ := strconv.ParseBool(field.Tag.Get("required"))
+
+		if required {
+			plan.required = append(plan.required, name)
+		}
+
+		enum, err := fieldEnum(field)
+
+		if err != nil {
+			return schemaPlan{}, err
+		}
+
+		if len(enum) > 0 {
+			plan.enums = append(plan.enums, fieldEnumPlan{index: i, name: name, enum: enum, required: required})
+		}
+	}
+
+	return plan, nil
+}
+
+// applyDefaults sets fields of struct value declaring default
+func (p *schemaPlan) applyDefaults(value reflect.Value) error {
+	for _, field := range p.defaults {
+		parsedValue, err := field.parse(field.values)
+
+		if err != nil {
+			return err
+		}
+
+		value.Field(field.index).Set(parsedValue)
+	}
+
+	return nil
+}
+
+// readsFields reports if fields present in raw body are checked under options
+func (p *schemaPlan) readsFields(options *BodyOptions) bool {
+	return len(p.enums) > 0 || options.RequireFields && len(p.required) > 0
+}
+
+// rawFields returns fields of raw json object of body, nil if it is not an object
+func rawFields(data []byte) map[string]json.RawMessage {
+	var fields map[string]json.RawMessage
+
+	// syntax errors are reported by decoding
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+
+	return fields
+}
+
+// checkRequired reports first required field missing in fields of raw body
+func (p *schemaPlan) checkRequired(fields map[string]json.RawMessage) error {
+	for _, name := range p.required {
+		if _, has := fields[name]; !has {
+			return fmt.Errorf("missing required field (%s)", name)
+		}
+	}
+
+	return nil
+}
+
+// validate checks fields of struct value against their enums, fields of raw body tell omitted fields
+func (p *schemaPlan) validate(value reflect.Value, fields map[string]json.RawMessage) error {
+	for _, field := range p.enums {
+		fieldValue := value.Field(field.index)
+
+		if fieldValue.Kind() == reflect.Pointer && fieldValue.IsNil() {
+			continue
+		}
+
+		// zero value of omitted optional field is not sent by client
+		if _, has := fields[field.name]; !has && !field.required && fieldValue.IsZero() {
+			continue
+		}
+
+		if err := checkEnum(fieldValue, field.enum); err != nil {
+			return fmt.Errorf("invalid value of field (%s): %w", field.name, err)
+		}
+	}
+
+	return nil
+}
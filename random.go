@@ -0,0 +1,116 @@
+package goapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nested schemas deeper than this are generated empty, so recursive schemas terminate
+const maxRandomDepth = 6
+
+const randomAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// RandomValue returns random json value conforming to schema: enum members, required and some optional
+// fields, numbers in range of format. Numbers are json.Number.
+func (d *SpecDocument) RandomValue(schema *SpecSchema, r *rand.Rand) any {
+	return d.randomValue(schema, r, 0)
+}
+
+func (d *SpecDocument) randomValue(schema *SpecSchema, r *rand.Rand, depth int) any {
+	schema = d.resolve(schema)
+
+	if schema == nil {
+		return nil
+	}
+
+	if len(schema.OneOf) > 0 {
+		return d.randomValue(schema.OneOf[r.IntN(len(schema.OneOf))], r, depth)
+	}
+
+	if len(schema.Enum) > 0 {
+		return schema.Enum[r.IntN(len(schema.Enum))]
+	}
+
+	switch schemaType(schema) {
+	case JsonBoolean:
+		return r.IntN(2) == 1
+	case JsonInteger:
+		return json.Number(strconv.Itoa(randomInteger(schema.Format, r)))
+	case JsonNumber:
+		return json.Number(strconv.FormatFloat(float64(r.IntN(200000)-100000)/100, 'f', -1, 64))
+	case JsonString:
+		return randomString(schema.Format, r)
+	case JsonArray:
+		if depth >= maxRandomDepth {
+			return []any{}
+		}
+		out := make([]any, r.IntN(4))
+		for i := range out {
+			out[i] = d.randomValue(schema.Items, r, depth+1)
+		}
+		return out
+	case JsonObject:
+		out := map[string]any{}
+		if depth >= maxRandomDepth {
+			return out
+		}
+		for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+			if slices.Contains(schema.Required, name) || r.IntN(2) == 1 {
+				out[name] = d.randomValue(schema.Properties[name], r, depth+1)
+			}
+		}
+		if schema.AdditionalProperties != nil && len(schema.Properties) == 0 {
+			for range r.IntN(3) {
+				out[randomString("", r)+"k"] = d.randomValue(schema.AdditionalProperties, r, depth+1)
+			}
+		}
+		return out
+	}
+
+	return nil
+}
+
+func randomInteger(format string, r *rand.Rand) int {
+	switch {
+	case strings.HasPrefix(format, "uint"):
+		return r.IntN(256)
+	case format == "int8":
+		return r.IntN(256) - 128
+	}
+
+	return r.IntN(20000) - 10000
+}
+
+func randomString(format string, r *rand.Rand) string {
+	switch format {
+	case "date-time":
+		return time.Unix(r.Int64N(4102444800), 0).UTC().Format(time.RFC3339)
+	case "date":
+		return time.Unix(r.Int64N(4102444800), 0).UTC().Format(time.DateOnly)
+	case "byte":
+		data := make([]byte, r.IntN(16))
+		for i := range data {
+			data[i] = byte(r.IntN(256))
+		}
+		return base64.StdEncoding.EncodeToString(data)
+	case "uuid":
+		return fmt.Sprintf("%08x-%04x-4%03x-a%03x-%012x", r.Uint32(), r.IntN(1<<16), r.IntN(1<<12), r.IntN(1<<12), r.Int64N(1<<48))
+	case "email":
+		return "user" + strconv.Itoa(r.IntN(1000)) + "@example.com"
+	}
+
+	var b strings.Builder
+
+	for range r.IntN(12) {
+		b.WriteByte(randomAlphabet[r.IntN(len(randomAlphabet))])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,296 @@
+package goapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type RateLimitAlgorithm int
+
+const (
+	// TokenBucket refills quota continuously and allows bursts up to limit
+	TokenBucket RateLimitAlgorithm = iota
+	// SlidingWindow counts requests in window weighted with previous window
+	SlidingWindow
+)
+
+// KeyFunc returns key of client whose requests share quota
+type KeyFunc = func(req *http.Request) string
+
+// RateLimit allows Limit requests per Window for each client key
+type RateLimit struct {
+	Limit int
+	// Window defaults to one minute
+	Window    time.Duration
+	Algorithm RateLimitAlgorithm
+	// Key defaults to ClientIP
+	Key KeyFunc
+	// Store defaults to in-memory store of policy
+	Store Store
+}
+
+// RateDecision is outcome of request against quota of key
+type RateDecision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is duration until quota is fully restored
+	Reset time.Duration
+	// RetryAfter is duration until next request is allowed, when rejected
+	RetryAfter time.Duration
+}
+
+// Store keeps quotas of keys, stores shared by instances allow distributed limits.
+// Requests are allowed when store fails.
+type Store interface {
+	Allow(ctx context.Context, key string, limit RateLimit) (RateDecision, error)
+}
+
+// ClientIP keys requests by remote address, proxies headers are not trusted
+func ClientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+
+	if err != nil {
+		return req.RemoteAddr
+	}
+
+	return host
+}
+
+// APIKey keys requests by value of header, requests without it share quota
+func APIKey(header string) KeyFunc {
+	return func(req *http.Request) string {
+		return req.Header.Get(header)
+	}
+}
+
+// rateLimitPolicy is rate limit of route or router group, routes of group share quota
+type rateLimitPolicy struct {
+	limit RateLimit
+	scope string
+}
+
+var rateLimitScopes atomic.Uint64
+
+// newRateLimitPolicy returns policy of limit, which must allow requests, zero values use defaults
+func newRateLimitPolicy(limit RateLimit) (*rateLimitPolicy, error) {
+	if limit.Limit <= 0 {
+		return nil, fmt.Errorf("invalid rate limit (%d), must be positive", limit.Limit)
+	}
+
+	if limit.Window < 0 {
+		return nil, fmt.Errorf("invalid rate limit window (%s), must be positive", limit.Window)
+	}
+
+	if limit.Window == 0 {
+		limit.Window = time.Minute
+	}
+
+	if limit.Key == nil {
+		limit.Key = ClientIP
+	}
+
+	if limit.Store == nil {
+		limit.Store = NewMemoryStore()
+	}
+
+	return &rateLimitPolicy{
+		limit: limit,
+		scope: strconv.FormatUint(rateLimitScopes.Add(1), 10),
+	}, nil
+}
+
+// RateLimit limits requests of routes registered later by router and its subrouters, routes share quota.
+// Invalid limit is reported as registration error.
+func (r *Router) RateLimit(limit RateLimit) {
+	policy, err := newRateLimitPolicy(limit)
+
+	if err != nil {
+		r.api.addRegistrationError("", "", "", fmt.Errorf("rate limit of router (%s): %w", r.prefix, err))
+		return
+	}
+
+	r.rateLimit = policy
+}
+
+// withRateLimit rejects requests exceeding quota of route with 429
+func withRateLimit(api *API, policy *rateLimitPolicy, handle httprouter.Handle) httprouter.Handle {
+	if policy == nil {
+		return handle
+	}
+
+	limit := policy.limit
+
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		decision, err := limit.Store.Allow(req.Context(), policy.scope+":"+limit.Key(req), limit)
+
+		if err != nil {
+			SpanFromContext(req.Context()).SetAttribute("ratelimit.error", err.Error())
+			handle(w, req, params)
+			return
+		}
+
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(seconds(decision.Reset)))
+
+		if !decision.Allowed {
+			header.Set("Retry-After", strconv.Itoa(seconds(decision.RetryAfter)))
+			invalidRequest(w, req, api.errorHandler, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+
+		handle(w, req, params)
+	}
+}
+
+// seconds rounds duration up to whole seconds
+func seconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
+
+// MemoryStore keeps quotas in memory of process
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*quota
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// quota is state of key, tokens of bucket or counts of windows
+type quota struct {
+	tokens   float64
+	previous int
+	current  int
+	start    time.Time
+	last     time.Time
+	window   time.Duration
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*quota),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Allow(ctx context.Context, key string, limit RateLimit) (RateDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	entry, has := s.entries[key]
+
+	if !has {
+		entry = &quota{
+			tokens: float64(limit.Limit),
+			start:  now,
+			last:   now,
+			window: limit.Window,
+		}
+		s.entries[key] = entry
+	}
+
+	if limit.Algorithm == SlidingWindow {
+		return entry.slidingWindow(now, limit), nil
+	}
+
+	return entry.tokenBucket(now, limit), nil
+}
+
+// sweep removes idle quotas, at most once per minute
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+
+	s.lastSweep = now
+
+	for key, entry := range s.entries {
+		if now.Sub(entry.last) > 2*entry.window {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func (q *quota) tokenBucket(now time.Time, limit RateLimit) RateDecision {
+	rate := float64(limit.Limit) / limit.Window.Seconds()
+
+	q.tokens = math.Min(float64(limit.Limit), q.tokens+now.Sub(q.last).Seconds()*rate)
+	q.last = now
+
+	decision := RateDecision{Limit: limit.Limit}
+
+	if q.tokens >= 1 {
+		q.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = time.Duration((1 - q.tokens) / rate * float64(time.Second))
+	}
+
+	decision.Remaining = int(q.tokens)
+	decision.Reset = time.Duration((float64(limit.Limit) - q.tokens) / rate * float64(time.Second))
+
+	return decision
+}
+
+func (q *quota) slidingWindow(now time.Time, limit RateLimit) RateDecision {
+	q.last = now
+
+	// move to window containing now
+	if elapsed := now.Sub(q.start); elapsed >= limit.Window {
+		windows := int(elapsed / limit.Window)
+		q.previous = q.current
+		if windows > 1 {
+			q.previous = 0
+		}
+		q.current = 0
+		q.start = q.start.Add(time.Duration(windows) * limit.Window)
+	}
+
+	elapsed := now.Sub(q.start)
+	weight := 1 - elapsed.Seconds()/limit.Window.Seconds()
+	count := float64(q.previous)*weight + float64(q.current)
+
+	decision := RateDecision{
+		Limit: limit.Limit,
+		Reset: limit.Window - elapsed,
+	}
+
+	if count+1 <= float64(limit.Limit) {
+		q.current++
+		count++
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = limit.Window - elapsed
+	}
+
+	decision.Remaining = max(limit.Limit-int(math.Ceil(count)), 0)
+
+	return decision
+}
+
+// rateLimitHeaders returns headers sent with responses of limited route
+func rateLimitHeaders(method EndpointMethod) []headerSpec {
+	if method.rateLimit == nil {
+		return nil
+	}
+
+	return []headerSpec{
+		{Name: "RateLimit-Limit", Type: JsonInteger},
+		{Name: "RateLimit-Remaining", Type: JsonInteger},
+		{Name: "RateLimit-Reset", Type: JsonInteger},
+	}
+}
@@ -0,0 +1,94 @@
+package goapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	appRouter.AddRoute("/v1", func(r *goapi.Router) {
+		r.RateLimit(goapi.RateLimit{Limit: 2, Window: time.Hour})
+		r.Post("/calculate", Calculate, goapi.RouteSpec{})
+		r.Get("/users/:id", GetUser, goapi.RouteSpec{})
+	})
+
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{
+		RateLimit: &goapi.RateLimit{Limit: 1, Window: time.Hour, Algorithm: goapi.SlidingWindow, Key: goapi.APIKey("X-API-Key")},
+	})
+
+	serve := func(method string, path string, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{"left": 1, "right": 2}`))
+		req.Header.Set("X-API-Key", apiKey)
+
+		recorder := httptest.NewRecorder()
+		api.Handler().ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	recorder := serve("POST", "/v1/calculate", "")
+	assert.Exactly(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", recorder.Header().Get("RateLimit-Remaining"))
+
+	// routes of group share quota
+	assert.Exactly(t, http.StatusOK, serve("GET", "/v1/users/1", "").Code)
+
+	recorder = serve("POST", "/v1/calculate", "")
+	assert.Exactly(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1800", recorder.Header().Get("Retry-After"))
+
+	var apiError goapi.DefaultErrorType
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &apiError))
+	assert.Equal(t, "rate limit exceeded", apiError.Detail)
+
+	assert.Exactly(t, http.StatusOK, serve("POST", "/calculate", "first").Code)
+	assert.Exactly(t, http.StatusTooManyRequests, serve("POST", "/calculate", "first").Code)
+	assert.Exactly(t, http.StatusOK, serve("POST", "/calculate", "second").Code, "Quota shared by api keys")
+
+	t.Chdir(t.TempDir())
+	assert.NoError(t, api.Setup())
+
+	spec, err := os.ReadFile("openapi.yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(spec), "'429':")
+	assert.Contains(t, string(spec), "Retry-After:")
+	assert.Contains(t, string(spec), "RateLimit-Remaining:")
+}
+
+func TestRateLimitInvalid(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	appRouter.AddRoute("/v1", func(r *goapi.Router) {
+		r.RateLimit(goapi.RateLimit{Limit: 0})
+		r.Post("/calculate", Calculate, goapi.RouteSpec{})
+	})
+
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{
+		RateLimit: &goapi.RateLimit{Limit: 5, Window: -time.Second},
+	})
+
+	err := api.Validate()
+
+	assert.ErrorContains(t, err, "rate limit of router (/v1): invalid rate limit (0), must be positive")
+	assert.ErrorContains(t, err, "invalid rate limit window (-1s), must be positive")
+
+	recorder := httptest.NewRecorder()
+	api.Handler().ServeHTTP(recorder, httptest.NewRequest("POST", "/v1/calculate", strings.NewReader(`{"left": 1, "right": 2}`)))
+
+	assert.Empty(t, recorder.Header().Get("RateLimit-Reset"), "Invalid limit applied")
+}
@@ -0,0 +1,63 @@
+package goapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// RegistrationError describes route or api misconfiguration detected during setup
+type RegistrationError struct {
+	Path     string
+	Method   Method
+	Function string
+	Err      error
+}
+
+func (e *RegistrationError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("goapi: %v", e.Err)
+	}
+
+	return fmt.Sprintf("goapi: %s %s (%s): %v", e.Method, e.Path, e.Function, e.Err)
+}
+
+func (e *RegistrationError) Unwrap() error {
+	return e.Err
+}
+
+func (api *API) addRegistrationError(path string, method Method, function string, err error) {
+	api.registrationErrors = append(api.registrationErrors, &RegistrationError{
+		Path:     path,
+		Method:   method,
+		Function: function,
+		Err:      err,
+	})
+}
+
+// Validate returns all errors collected during registration, joined
+func (api *API) Validate() error {
+	return errors.Join(api.registrationErrors...)
+}
+
+// invalidRouteHandle responds to routes which failed to register
+func invalidRouteHandle(api *API) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		writeError(w, req, api.errorHandler, NewAPIError(http.StatusInternalServerError, "invalid route", nil))
+	}
+}
+
+// handleRoute adds route to router, httprouter reports conflicts by panic
+func handleRoute(router *httprouter.Router, method string, path string, handle httprouter.Handle) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	router.Handle(method, path, handle)
+
+	return nil
+}
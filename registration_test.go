@@ -0,0 +1,71 @@
+package goapi_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func InvalidReturn(calc Calculation) Result {
+	return Result{}
+}
+
+func TestRegistrationErrors(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{})
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{})
+	handle := appRouter.Post("/invalid", InvalidReturn, goapi.RouteSpec{})
+
+	err := api.Validate()
+
+	assert.Error(t, err, "Misconfiguration not detected")
+
+	var registrationErrors []*goapi.RegistrationError
+
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var registrationError *goapi.RegistrationError
+		if errors.As(err, &registrationError) {
+			registrationErrors = append(registrationErrors, registrationError)
+		}
+	}
+
+	assert.Len(t, registrationErrors, 2)
+	assert.Equal(t, "/calculate", registrationErrors[0].Path)
+	assert.Equal(t, goapi.MethodPost, registrationErrors[0].Method)
+	assert.Equal(t, "Calculate", registrationErrors[0].Function)
+	assert.Equal(t, "/invalid", registrationErrors[1].Path)
+	assert.Equal(t, "InvalidReturn", registrationErrors[1].Function)
+
+	assert.Equal(t, err, api.Setup(), "Setup must return registration errors")
+	assert.Panics(t, api.MustSetup)
+
+	recorder := httptest.NewRecorder()
+	handle(recorder, httptest.NewRequest("POST", "/invalid", nil), httprouter.Params{})
+
+	assert.Exactly(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestRegistrationRejectedRoute(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	appRouter.Post("/calculate/sum", Calculate, goapi.RouteSpec{})
+	appRouter.Post("/calculate/*operation", Calculate, goapi.RouteSpec{})
+
+	var registrationError *goapi.RegistrationError
+
+	if assert.ErrorAs(t, api.Validate(), &registrationError, "Conflicting route not reported") {
+		assert.Equal(t, "/calculate/*operation", registrationError.Path)
+	}
+
+	if assert.Len(t, api.Endpoints, 1, "Rejected route documented") {
+		assert.Equal(t, "/calculate/sum", api.Endpoints[0].Path)
+	}
+}
@@ -7,8 +7,10 @@ import (
 )
 
 type Router struct {
-	api    *API
-	prefix string
+	api       *API
+	prefix    string
+	cors      *corsPolicy
+	rateLimit *rateLimitPolicy
 }
 
 type Method string
@@ -18,6 +20,8 @@ const (
 	MethodPost    Method = "post"
 	MethodPut     Method = "put"
 	MethodOptions Method = "options"
+	MethodDelete  Method = "delete"
+	MethodPatch   Method = "patch"
 )
 
 type RouterHandler = func(*Router)
@@ -27,27 +31,46 @@ type RouteSpec struct {
 	Summary     string
 	Description string
 	OperationId string
+	// Body overrides api-wide BodyOptions
+	Body *BodyOptions
+	// Responses are documented in addition to default responses
+	Responses []ResponseEntry
+	// RateLimit overrides rate limit of router, quota is not shared with other routes
+	RateLimit *RateLimit
+
+	cors      *corsPolicy
+	rateLimit *rateLimitPolicy
+	// untagged routes do not get default tag of router
+	untagged bool
+}
+
+// ResponseEntry documents response of route by status, Schema names registered schema
+type ResponseEntry struct {
+	Status      string
+	Description string
+	Schema      string
 }
 
 func (r *Router) AddRoute(prefix string, handler RouterHandler) {
 	router := Router{
-		api:    r.api,
-		prefix: joinPrefix(r.prefix, prefix),
+		api:       r.api,
+		prefix:    joinPrefix(r.prefix, prefix),
+		cors:      r.cors,
+		rateLimit: r.rateLimit,
 	}
 
 	handler(&router)
 }
 
 func (r *Router) Route(method Method, prefix string, fn Endpoint, spec RouteSpec) httprouter.Handle {
-	fullPath := joinPrefix(r.prefix, prefix)
+	fullPath, spec := r.resolve(prefix, spec)
 
-	if len(spec.Tags) == 0 {
-		if tag := defaultTag(r.prefix); tag != "" {
-			spec.Tags = append(spec.Tags, tag)
-		}
-	}
+	ep, err := newEndpointMethod(r.api, method, fullPath, fn, spec)
 
-	ep := newEndpointMethod(r.api, method, fullPath, fn, spec)
+	if err != nil {
+		r.api.addRegistrationError(fullPath, method, getFunctionName(fn), err)
+		return invalidRouteHandle(r.api)
+	}
 
 	return ep.Handler
 }
@@ -65,6 +88,21 @@ func (r *Router) Options(prefix string, fn Endpoint, spec RouteSpec) httprouter.
 	return r.Route(MethodOptions, prefix, fn, spec)
 }
 
+// resolve returns full path of route and spec with default tag and cors policy of router applied
+func (r *Router) resolve(prefix string, spec RouteSpec) (string, RouteSpec) {
+	fullPath := joinPrefix(r.prefix, prefix)
+	spec.cors = r.cors
+	spec.rateLimit = r.rateLimit
+
+	if len(spec.Tags) == 0 && !spec.untagged {
+		if tag := defaultTag(r.prefix); tag != "" {
+			spec.Tags = append(spec.Tags, tag)
+		}
+	}
+
+	return fullPath, spec
+}
+
 func joinPrefix(base, segment string) string {
 	base = strings.TrimSuffix(base, "/")
 
@@ -3,18 +3,37 @@ package goapi
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Property struct {
 	Name string
 	Meta Meta
+	// Required is set by required tag of field, presence in request bodies is checked with BodyOptions.RequireFields
+	Required bool
 }
 
 type Schema struct {
 	sourceType reflect.Type
 	Name       string
 	Properties []Property
+	// Meta is set for schemas which are not plain objects (enums, custom schemas)
+	Meta *Meta
+}
+
+// Required returns names of required properties
+func (s Schema) Required() []string {
+	var out []string
+
+	for _, prop := range s.Properties {
+		if prop.Required {
+			out = append(out, prop.Name)
+		}
+	}
+
+	return out
 }
 
 type Schemas []Schema
@@ -27,48 +46,121 @@ func (s *Schemas) RegisterSchema(Type reflect.Type) (Schema, error) {
 		}
 	}
 
+	if custom := extractJSONSchema(Type); custom != nil {
+		meta := customMeta(custom)
+
+		schema := Schema{
+			sourceType: Type,
+			Name:       Type.Name(),
+			Meta:       &meta,
+		}
+
+		*s = append(*s, schema)
+
+		return schema, nil
+	}
+
+	if Type.Kind() != reflect.Struct {
+		return Schema{}, fmt.Errorf("invalid schema type (%s), must be struct", Type)
+	}
+
+	properties, err := buildProperties(Type)
+
+	if err != nil {
+		return Schema{}, err
+	}
+
 	schema := Schema{
 		sourceType: Type,
 		Name:       Type.Name(),
-		Properties: []Property{},
+		Properties: properties,
 	}
 
+	*s = append(*s, schema)
+
+	return schema, nil
+}
+
+// jsonFieldName returns name of field as seen by encoding/json, false if field is skipped
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag := field.Tag.Get("json")
+
+	if tag == "-" {
+		return "", false
+	}
+
+	name := field.Name
+	if tag != "" {
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			name = parts[0]
+		}
+	}
+
+	return name, true
+}
+
+func buildProperties(Type reflect.Type) ([]Property, error) {
+	properties := []Property{}
+
 	for i := 0; i < Type.NumField(); i++ {
 		field := Type.Field(i)
 
-		if !field.IsExported() {
-			continue
-		}
 		// handle json tag part
-		if tag := field.Tag.Get("json"); tag == "-" {
-			continue
-		}
+		name, ok := jsonFieldName(field)
 
-		name := field.Name
-		if tag := field.Tag.Get("json"); tag != "" {
-			parts := strings.Split(tag, ",")
-			if parts[0] != "" {
-				name = parts[0]
-			}
+		if !ok {
+			continue
 		}
 		// build meta (openapi schema) part
 		fieldType := field.Type
 
-		jt, err := resolveJsonType(fieldType)
+		meta, err := resolveMeta(fieldType)
 
 		if err != nil {
-			return Schema{}, err
+			return nil, err
+		}
+
+		if tag, has := field.Tag.Lookup("format"); has {
+			meta.Rest["format"] = tag
 		}
 
-		meta := BuildFieldMeta(jt.jsonType, field)
-		// use default if not defined
-		if _, has := meta.Rest["format"]; !has && jt.format != "" {
-			meta.Rest["format"] = jt.format
+		if _, has := field.Tag.Lookup("enum"); has {
+			enum, err := fieldEnum(field)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid enum of field (%s): %w", field.Name, err)
+			}
+
+			target := &meta
+			if meta.Type == JsonArray {
+				target = meta.Items
+			}
+
+			target.Rest["enum"] = formatEnum(enum)
+			target.enumType = nil
 		}
 
-		schema.Properties = append(schema.Properties, Property{
-			Name: name,
-			Meta: meta,
+		if value, has := fieldDefault(field); has {
+			_, encoded, err := parseDefault(value, fieldType, StyleUndefined)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid default of field (%s): %w", field.Name, err)
+			}
+
+			meta.Rest["default"] = encoded
+		}
+
+		required, _ := strconv.ParseBool(field.Tag.Get("required"))
+
+		properties = append(properties, Property{
+			Name:     name,
+			Meta:     meta,
+			Required: required,
 		})
 		// //Add nested schemas (TODO: handle it first (add as ref))
 		// if fieldType.Kind() == reflect.Struct {
@@ -76,9 +168,55 @@ func (s *Schemas) RegisterSchema(Type reflect.Type) (Schema, error) {
 		// }
 	}
 
-	*s = append(*s, schema)
+	return properties, nil
+}
 
-	return schema, nil
+// resolveMeta builds meta of type, including items of arrays and values of maps
+func resolveMeta(Type reflect.Type) (Meta, error) {
+	if custom := extractJSONSchema(Type); custom != nil {
+		return customMeta(custom), nil
+	}
+
+	jt, err := resolveJsonType(Type)
+
+	if err != nil {
+		return Meta{}, err
+	}
+
+	meta := BuildTypeMeta(jt.jsonType, Type)
+
+	if _, has := meta.Rest["format"]; !has && jt.format != "" {
+		meta.Rest["format"] = jt.format
+	}
+
+	if err := resolveNestedMeta(&meta, Type); err != nil {
+		return Meta{}, err
+	}
+
+	return meta, nil
+}
+
+func resolveNestedMeta(meta *Meta, Type reflect.Type) error {
+	if meta.Custom != nil {
+		return nil
+	}
+
+	switch meta.Type {
+	case JsonArray:
+		items, err := resolveMeta(derefType(Type).Elem())
+		if err != nil {
+			return err
+		}
+		meta.Items = &items
+	case JsonObject:
+		values, err := resolveMeta(derefType(Type).Elem())
+		if err != nil {
+			return err
+		}
+		meta.AdditionalProperties = &values
+	}
+
+	return nil
 }
 
 type jsonTypeDescriptor struct {
@@ -90,6 +228,18 @@ func resolveJsonType(Type reflect.Type) (jsonTypeDescriptor, error) {
 
 	Type = derefType(Type)
 
+	if Type == GetType[time.Time]() {
+		return jsonTypeDescriptor{jsonType: JsonString, format: "date-time"}, nil
+	}
+
+	// types parsed from text are represented by strings unless they are scalars
+	if implementsParser(Type) {
+		switch Type.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+			return jsonTypeDescriptor{jsonType: JsonString}, nil
+		}
+	}
+
 	switch Type.Kind() {
 	case reflect.Bool:
 		return jsonTypeDescriptor{jsonType: JsonBoolean}, nil
@@ -119,6 +269,17 @@ func resolveJsonType(Type reflect.Type) (jsonTypeDescriptor, error) {
 		return jsonTypeDescriptor{jsonType: JsonNumber, format: "double"}, nil
 	case reflect.String:
 		return jsonTypeDescriptor{jsonType: JsonString}, nil
+	case reflect.Slice, reflect.Array:
+		// encoding/json marshals []byte as base64 string
+		if Type.Kind() == reflect.Slice && Type.Elem().Kind() == reflect.Uint8 {
+			return jsonTypeDescriptor{jsonType: JsonString, format: "byte"}, nil
+		}
+		return jsonTypeDescriptor{jsonType: JsonArray}, nil
+	case reflect.Map:
+		if Type.Key().Kind() != reflect.String {
+			return jsonTypeDescriptor{}, fmt.Errorf("invalid map key type (%s)", Type.Key())
+		}
+		return jsonTypeDescriptor{jsonType: JsonObject}, nil
 	default:
 		return jsonTypeDescriptor{}, fmt.Errorf("invalid type (%s)", reflect.TypeOf(Type).Name())
 	}
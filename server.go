@@ -1,5 +1,17 @@
 package goapi
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
 type ServerEntry struct {
 	Url         string
 	Description string
@@ -24,3 +36,155 @@ func (s *Servers) SetDescription(url string, description string) {
 
 	(*s)[index].Description = description
 }
+
+// Hook is run on server startup or shutdown
+type Hook = func(ctx context.Context) error
+
+// ServeOptions configures server started by API.ListenAndServe, zero values use defaults
+type ServeOptions struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout limits draining of active connections on shutdown
+	ShutdownTimeout time.Duration
+	// CertFile and KeyFile enable TLS when both are set
+	CertFile string
+	KeyFile  string
+	// RegisterServer adds actual listen address to Servers before startup hooks run
+	RegisterServer bool
+}
+
+const (
+	defaultReadTimeout       = 30 * time.Second
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultShutdownTimeout   = 15 * time.Second
+)
+
+func orDefault(value time.Duration, fallback time.Duration) time.Duration {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+// OnStartup registers hook run after listener is bound, before requests are served
+func (api *API) OnStartup(hook Hook) {
+	api.startupHooks = append(api.startupHooks, hook)
+}
+
+// OnShutdown registers hook run after active connections are drained
+func (api *API) OnShutdown(hook Hook) {
+	api.shutdownHooks = append(api.shutdownHooks, hook)
+}
+
+// ListenAndServe serves api on addr until ctx is cancelled or process receives SIGTERM or interrupt,
+// then shuts server down gracefully. Returns nil after graceful shutdown, registration errors are returned
+// before listening.
+func (api *API) ListenAndServe(ctx context.Context, addr string, opts ServeOptions) error {
+	if err := api.Validate(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	tls := opts.CertFile != "" && opts.KeyFile != ""
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           api.Handler(),
+		ReadTimeout:       orDefault(opts.ReadTimeout, defaultReadTimeout),
+		ReadHeaderTimeout: orDefault(opts.ReadHeaderTimeout, defaultReadHeaderTimeout),
+		WriteTimeout:      orDefault(opts.WriteTimeout, defaultWriteTimeout),
+		IdleTimeout:       orDefault(opts.IdleTimeout, defaultIdleTimeout),
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithoutCancel(ctx)
+		},
+	}
+
+	listener, err := net.Listen("tcp", addr)
+
+	if err != nil {
+		return err
+	}
+
+	if opts.RegisterServer {
+		api.Servers.Set(serverURL(listener.Addr(), tls))
+	}
+
+	for _, hook := range api.startupHooks {
+		if err := hook(ctx); err != nil {
+			listener.Close()
+			return fmt.Errorf("startup hook: %w", err)
+		}
+	}
+
+	served := make(chan error, 1)
+
+	go func() {
+		if tls {
+			served <- server.ServeTLS(listener, opts.CertFile, opts.KeyFile)
+		} else {
+			served <- server.Serve(listener)
+		}
+	}()
+
+	var failed error
+
+	select {
+	case failed = <-served:
+		// server failed before shutdown was requested, startup hooks already ran so shutdown hooks run too
+	case <-ctx.Done():
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), orDefault(opts.ShutdownTimeout, defaultShutdownTimeout))
+	defer cancel()
+
+	if failed != nil {
+		return errors.Join(append([]error{failed}, api.runShutdownHooks(drainCtx)...)...)
+	}
+
+	errs := append([]error{server.Shutdown(drainCtx)}, api.runShutdownHooks(drainCtx)...)
+
+	if err := <-served; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// runShutdownHooks runs all shutdown hooks, returning their errors
+func (api *API) runShutdownHooks(ctx context.Context) []error {
+	var errs []error
+
+	for _, hook := range api.shutdownHooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook: %w", err))
+		}
+	}
+
+	return errs
+}
+
+// serverURL returns url of listen address, unspecified hosts are replaced by localhost
+func serverURL(addr net.Addr, tls bool) string {
+	scheme := "http"
+	if tls {
+		scheme = "https"
+	}
+
+	host, port, err := net.SplitHostPort(addr.String())
+
+	if err != nil {
+		return scheme + "://" + addr.String()
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+
+	return scheme + "://" + net.JoinHostPort(host, port)
+}
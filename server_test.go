@@ -0,0 +1,105 @@
+package goapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenAndServe(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	served := make(chan error, 1)
+	started := make(chan string, 1)
+	stopped := false
+
+	api.OnStartup(func(ctx context.Context) error {
+		started <- api.Servers[len(api.Servers)-1].Url
+		return nil
+	})
+	api.OnShutdown(func(ctx context.Context) error {
+		stopped = true
+		return nil
+	})
+
+	go func() {
+		served <- api.ListenAndServe(ctx, "127.0.0.1:0", goapi.ServeOptions{RegisterServer: true})
+	}()
+
+	var url string
+	select {
+	case url = <-started:
+	case err := <-served:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not start")
+	}
+
+	assert.True(t, strings.HasPrefix(url, "http://127.0.0.1:"), "Listen address not registered")
+
+	res, err := http.Post(url+"/calculate", "application/json", strings.NewReader(`{"left": 2, "right": 3}`))
+	assert.NoError(t, err)
+
+	var result Result
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+	res.Body.Close()
+	assert.Exactly(t, http.StatusOK, res.StatusCode)
+	assert.Exactly(t, 5, result.Result)
+
+	cancel()
+
+	select {
+	case err := <-served:
+		assert.NoError(t, err, "Graceful shutdown must not fail")
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+
+	assert.True(t, stopped, "Shutdown hooks not called")
+}
+
+func TestListenAndServeRegistrationErrors(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	appRouter.Post("/invalid", InvalidReturn, goapi.RouteSpec{})
+
+	started := false
+	api.OnStartup(func(ctx context.Context) error {
+		started = true
+		return nil
+	})
+
+	err := api.ListenAndServe(context.Background(), "127.0.0.1:0", goapi.ServeOptions{})
+
+	assert.Equal(t, api.Validate(), err, "Registration errors not returned")
+	assert.False(t, started, "Api with registration errors started")
+}
+
+func TestListenAndServeFailure(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+
+	stopped := false
+	api.OnShutdown(func(ctx context.Context) error {
+		stopped = true
+		return nil
+	})
+
+	// certificate is loaded by server after startup hooks ran
+	err := api.ListenAndServe(context.Background(), "127.0.0.1:0", goapi.ServeOptions{CertFile: "missing.pem", KeyFile: "missing.pem"})
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.True(t, stopped, "Shutdown hooks not called after server failure")
+}
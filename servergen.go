@@ -0,0 +1,753 @@
+package goapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+type ServerOptions struct {
+	// Package of generated file, api by default
+	Package string
+	// Func is name of generated registration function, RegisterRoutes by default
+	Func string
+}
+
+type serverData struct {
+	Package    string
+	Func       string
+	Imports    []string
+	Types      []serverType
+	Schemas    []string
+	Operations []serverOperation
+}
+
+// serverType is declaration of generated type, struct when Alias and Underlying are empty
+type serverType struct {
+	Name       string
+	Alias      string
+	Underlying string
+	Fields     []serverField
+	Param      *serverParam
+	Format     string
+	Enum       string
+	Default    string
+	Schema     string
+}
+
+type serverField struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+type serverParam struct {
+	Name        string
+	In          string
+	Required    bool
+	Description string
+	Style       string
+	Explode     *bool
+}
+
+type serverInput struct {
+	Name string
+	Type string
+}
+
+type serverOperation struct {
+	Name        string
+	Verb        string
+	Method      string
+	Path        string
+	Inputs      []serverInput
+	Output      string
+	Status      int
+	Tags        string
+	Summary     string
+	Description string
+	OperationId string
+	Responses   []ResponseEntry
+}
+
+// defaultResponses are documented by goapi for every operation
+var defaultResponses = []string{"2XX", "400", "401", "403", "404", "413", "415", "422", "429", "500", "default"}
+
+var pathTemplate = regexp.MustCompile(`\{([^}]+)\}`)
+
+var serverMethods = map[string]string{
+	"get":     "goapi.MethodGet",
+	"post":    "goapi.MethodPost",
+	"put":     "goapi.MethodPut",
+	"options": "goapi.MethodOptions",
+	"delete":  "goapi.MethodDelete",
+	"patch":   "goapi.MethodPatch",
+}
+
+// serverGenerator maps schemas of document to go types, declaring named types as it goes
+type serverGenerator struct {
+	document *SpecDocument
+	data     serverData
+	names    map[string]bool
+	imports  map[string]bool
+}
+
+// GenerateServer writes go source with types of document components, parameter types, endpoint stubs
+// and registration function of routes, so the api regenerates equivalent document.
+//
+// Objects nested in schemas, and schemas goapi does not model, are declared as types providing
+// their schema by JSONSchema. Number without format is generated as float64, which goapi documents as double.
+func GenerateServer(w io.Writer, document *SpecDocument, opts ServerOptions) error {
+	g := &serverGenerator{
+		document: document,
+		names:    map[string]bool{},
+		imports:  map[string]bool{"context": true, "net/http": true, "reflect": true},
+		data: serverData{
+			Package: opts.Package,
+			Func:    opts.Func,
+		},
+	}
+
+	if g.data.Package == "" {
+		g.data.Package = "api"
+	}
+
+	if g.data.Func == "" {
+		g.data.Func = "RegisterRoutes"
+	}
+
+	g.names[g.data.Func] = true
+
+	// components keep their names, so refs resolve to them
+	componentNames := slices.Sorted(maps.Keys(document.Components.Schemas))
+
+	for _, name := range componentNames {
+		g.names[goIdentifier(name, true)] = true
+	}
+
+	for _, name := range componentNames {
+		if err := g.component(name, document.Components.Schemas[name]); err != nil {
+			return fmt.Errorf("components.schemas.%s: %w", name, err)
+		}
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(document.Paths)) {
+		for _, method := range specMethods {
+			operation := document.Paths[path][method]
+
+			if operation == nil {
+				continue
+			}
+
+			if err := g.operation(path, method, operation); err != nil {
+				return fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+		}
+	}
+
+	g.data.Imports = slices.Sorted(maps.Keys(g.imports))
+
+	tmpl, err := template.ParseFS(templateFS, "server.go.tmpl")
+
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, g.data); err != nil {
+		return err
+	}
+
+	source, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		return fmt.Errorf("format generated server: %w", err)
+	}
+
+	_, err = w.Write(source)
+
+	return err
+}
+
+// component declares type of component schema, objects and custom schemas are registered by name
+func (g *serverGenerator) component(name string, schema *SpecSchema) error {
+	typeName := goIdentifier(name, true)
+
+	if typeName != name {
+		return fmt.Errorf("name is not valid go identifier")
+	}
+
+	switch {
+	case typeName == GetType[DefaultErrorType]().Name():
+		// error schema of goapi, documented once when alias is registered
+		g.declare(serverType{Name: typeName, Alias: "goapi." + typeName})
+		g.data.Schemas = append(g.data.Schemas, typeName)
+	case schema.Ref == "" && len(schema.Enum) > 0 && len(schema.Type) > 0:
+		// named enums are inlined where used
+		underlying, err := g.scalar(schema)
+		if err != nil {
+			return err
+		}
+		g.declare(serverType{Name: typeName, Underlying: underlying, Enum: goLiteral(schema.Enum)})
+	case g.isStruct(schema):
+		if err := g.declareStruct(typeName, schema); err != nil {
+			return err
+		}
+		g.data.Schemas = append(g.data.Schemas, typeName)
+	default:
+		g.declareCustom(typeName, schema)
+		g.data.Schemas = append(g.data.Schemas, typeName)
+	}
+
+	return nil
+}
+
+// isStruct reports if schema is object with properties
+func (g *serverGenerator) isStruct(schema *SpecSchema) bool {
+	return schema.Ref == "" && len(schema.Properties) > 0 && (len(schema.Type) == 0 || slices.Contains(schema.Type, string(JsonObject)))
+}
+
+func (g *serverGenerator) declare(declaration serverType) {
+	g.names[declaration.Name] = true
+	g.data.Types = append(g.data.Types, declaration)
+}
+
+// declareStruct declares struct of object schema, fields of nested schemas get types named after the field
+func (g *serverGenerator) declareStruct(name string, schema *SpecSchema) error {
+	declaration := serverType{Name: name}
+	fieldNames := map[string]bool{}
+
+	for _, property := range slices.Sorted(maps.Keys(schema.Properties)) {
+		propertySchema := schema.Properties[property]
+		fieldName := uniqueName(goIdentifier(property, true), fieldNames)
+		required := slices.Contains(schema.Required, property)
+
+		fieldType, tags, err := g.field(name+fieldName, propertySchema)
+
+		if err != nil {
+			return fmt.Errorf("property %s: %w", property, err)
+		}
+
+		jsonTag := property
+		if !required {
+			jsonTag += ",omitempty"
+		}
+
+		tags = append([]string{fmt.Sprintf("json:%q", jsonTag)}, tags...)
+
+		if required {
+			tags = append(tags, `required:"true"`)
+		}
+
+		declaration.Fields = append(declaration.Fields, serverField{
+			Name: fieldName,
+			Type: fieldType,
+			Tag:  strings.Join(tags, " "),
+		})
+	}
+
+	g.declare(declaration)
+
+	return nil
+}
+
+// field returns type and tags of struct field of schema
+func (g *serverGenerator) field(name string, schema *SpecSchema) (string, []string, error) {
+	resolved := g.document.resolve(schema)
+
+	// refs to named enums keep their type
+	if schema.Ref != "" && resolved != nil && len(resolved.Enum) > 0 {
+		return strings.TrimPrefix(schema.Ref, "#/components/schemas/"), nil, nil
+	}
+
+	fieldType, ok := g.native(schema)
+
+	if !ok {
+		return g.declareCustom(uniqueName(name, g.names), schema), nil, nil
+	}
+
+	fieldType = g.elementType(name, schema, fieldType)
+
+	var tags []string
+
+	if schema.Format != "" && nativeFormat(schema) != schema.Format {
+		tags = append(tags, fmt.Sprintf("format:%q", schema.Format))
+	}
+
+	if len(schema.Enum) > 0 {
+		values := make([]string, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			values = append(values, fmt.Sprint(value))
+		}
+		tags = append(tags, fmt.Sprintf("enum:%q", strings.Join(values, ",")))
+	}
+
+	if schema.Default != nil && schema.Items == nil {
+		tags = append(tags, fmt.Sprintf("default:%q", fmt.Sprint(schema.Default)))
+	}
+
+	return fieldType, tags, nil
+}
+
+// elementType declares type of items of array, or values of map, which carry format or enum,
+// so they are documented by the type. It returns native type with element replaced by declared one.
+func (g *serverGenerator) elementType(name string, schema *SpecSchema, native string) string {
+	element, prefix, suffix := schema.Items, "[]", "Item"
+
+	if schemaType(schema) == JsonObject {
+		element, prefix, suffix = schema.AdditionalProperties, "map[string]", "Value"
+	}
+
+	if element == nil || element.Ref != "" {
+		return native
+	}
+
+	declaration := serverType{Underlying: strings.TrimPrefix(native, prefix)}
+
+	if element.Format != "" && nativeFormat(element) != element.Format {
+		declaration.Format = element.Format
+	}
+
+	if len(element.Enum) > 0 {
+		declaration.Enum = goLiteral(element.Enum)
+	}
+
+	if declaration.Format == "" && declaration.Enum == "" {
+		return native
+	}
+
+	declaration.Name = uniqueName(name+suffix, g.names)
+	g.declare(declaration)
+
+	return prefix + declaration.Name
+}
+
+// native returns go type of scalar schema or named enum, or array and map of them, false if schema needs custom type
+func (g *serverGenerator) native(schema *SpecSchema) (string, bool) {
+	if schema == nil {
+		return "", false
+	}
+
+	if schema.Ref != "" {
+		// refs to named enums, e.g. items of enum components, keep their type
+		if resolved := g.document.resolve(schema); resolved != nil && len(resolved.Enum) > 0 && len(resolved.Type) > 0 {
+			return strings.TrimPrefix(schema.Ref, "#/components/schemas/"), true
+		}
+
+		return "", false
+	}
+
+	switch schemaType(schema) {
+	case JsonArray:
+		if schema.Items == nil {
+			return "", false
+		}
+		items, ok := g.native(schema.Items)
+		if !ok || schemaType(schema.Items) == JsonObject || schemaType(schema.Items) == JsonArray {
+			return "", false
+		}
+		return "[]" + items, true
+	case JsonObject:
+		if len(schema.Properties) > 0 || schema.AdditionalProperties == nil {
+			return "", false
+		}
+		values, ok := g.native(schema.AdditionalProperties)
+		if !ok {
+			return "", false
+		}
+		return "map[string]" + values, true
+	case "":
+		return "", false
+	}
+
+	scalar, err := g.scalar(schema)
+
+	return scalar, err == nil
+}
+
+// scalar returns go type of scalar schema by its type and format
+func (g *serverGenerator) scalar(schema *SpecSchema) (string, error) {
+	switch schemaType(schema) {
+	case JsonBoolean:
+		return "bool", nil
+	case JsonInteger:
+		switch schema.Format {
+		case "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			return schema.Format, nil
+		}
+		return "int", nil
+	case JsonNumber:
+		if schema.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case JsonString:
+		switch schema.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time", nil
+		case "byte":
+			return "[]byte", nil
+		}
+		return "string", nil
+	}
+
+	return "", fmt.Errorf("unsupported type (%s)", schema.Type)
+}
+
+// nativeFormat returns format documented by goapi for go type generated of schema
+func nativeFormat(schema *SpecSchema) string {
+	switch schemaType(schema) {
+	case JsonInteger:
+		switch schema.Format {
+		case "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			return schema.Format
+		}
+	case JsonNumber:
+		if schema.Format == "float" {
+			return "float"
+		}
+		return "double"
+	case JsonString:
+		if schema.Format == "date-time" || schema.Format == "byte" {
+			return schema.Format
+		}
+	}
+
+	return ""
+}
+
+// schemaType returns first non null type of schema
+func schemaType(schema *SpecSchema) JsonType {
+	for _, t := range schema.Type {
+		if t != string(JsonNull) {
+			return JsonType(t)
+		}
+	}
+
+	if len(schema.Properties) > 0 {
+		return JsonObject
+	}
+
+	return ""
+}
+
+// declareCustom declares type providing schema as decoded from document
+func (g *serverGenerator) declareCustom(name string, schema *SpecSchema) string {
+	g.declare(serverType{
+		Name:       name,
+		Underlying: g.shape(schema),
+		Schema:     goLiteral(schema.raw),
+	})
+
+	return name
+}
+
+// shape returns go type able to hold json values of schema
+func (g *serverGenerator) shape(schema *SpecSchema) string {
+	if schema == nil {
+		return "map[string]any"
+	}
+
+	if schema.Ref != "" {
+		resolved := g.document.resolve(schema)
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+
+		if resolved != nil && (g.isStruct(resolved) || len(resolved.Enum) > 0) {
+			return name
+		}
+
+		return g.shape(resolved)
+	}
+
+	if native, ok := g.native(schema); ok {
+		return native
+	}
+
+	switch schemaType(schema) {
+	case JsonArray:
+		return "[]" + g.shape(schema.Items)
+	case JsonObject, "":
+		return "map[string]any"
+	}
+
+	scalar, _ := g.scalar(schema)
+
+	return scalar
+}
+
+// operation declares parameter and body types and stub of operation
+func (g *serverGenerator) operation(path, method string, operation *SpecOperation) error {
+	methodExpr, ok := serverMethods[method]
+
+	if !ok {
+		return fmt.Errorf("method is not supported")
+	}
+
+	routePath := pathTemplate.ReplaceAllString(path, ":$1")
+
+	name := goIdentifier(operation.OperationId, true)
+	if operation.OperationId == "" {
+		name = operationName(routePath, EndpointMethod{Method: Method(method)})
+	}
+	name = uniqueName(name, g.names)
+
+	stub := serverOperation{
+		Name:        name,
+		Verb:        strings.ToUpper(method),
+		Method:      methodExpr,
+		Path:        routePath,
+		Summary:     operation.Summary,
+		Description: operation.Description,
+		OperationId: operation.OperationId,
+		Status:      http.StatusOK,
+	}
+
+	if len(operation.Tags) > 0 {
+		stub.Tags = fmt.Sprintf("%#v", operation.Tags)
+	}
+
+	args := map[string]bool{"ctx": true, "r": true}
+
+	for _, param := range operation.Parameters {
+		typeName, err := g.parameter(name, param)
+
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", param.Name, err)
+		}
+
+		stub.Inputs = append(stub.Inputs, serverInput{Name: uniqueName(goIdentifier(param.Name, false), args), Type: typeName})
+	}
+
+	if body := operation.RequestBody; body != nil {
+		schema := jsonSchemaOf(body.Content)
+
+		if schema == nil {
+			return fmt.Errorf("request body must have json content")
+		}
+
+		typeName, err := g.bodyType(name+"Request", schema)
+
+		if err != nil {
+			return fmt.Errorf("request body: %w", err)
+		}
+
+		stub.Inputs = append(stub.Inputs, serverInput{Name: uniqueName("body", args), Type: typeName})
+	}
+
+	if err := g.responses(&stub, operation.Responses); err != nil {
+		return err
+	}
+
+	g.data.Operations = append(g.data.Operations, stub)
+
+	return nil
+}
+
+// responses sets output and status of stub by success response, other responses are documented by entries
+func (g *serverGenerator) responses(stub *serverOperation, responses map[string]*SpecResponse) error {
+	success := ""
+
+	for _, status := range slices.Sorted(maps.Keys(responses)) {
+		if strings.HasPrefix(status, "2") {
+			success = status
+			break
+		}
+	}
+
+	for _, status := range slices.Sorted(maps.Keys(responses)) {
+		response := responses[status]
+		schema := jsonSchemaOf(response.Content)
+
+		if status == success {
+			if schema != nil {
+				typeName, err := g.bodyType(stub.Name+"Response", schema)
+
+				if err != nil {
+					return fmt.Errorf("response %s: %w", status, err)
+				}
+
+				stub.Output = typeName
+			}
+
+			if code, err := strconv.Atoi(status); err == nil {
+				stub.Status = code
+			}
+
+			if status == "2XX" {
+				continue
+			}
+		} else if slices.Contains(defaultResponses, status) {
+			continue
+		}
+
+		entry := ResponseEntry{Status: status, Description: response.Description}
+
+		if schema != nil {
+			typeName := stub.Output
+
+			if status != success {
+				var err error
+				typeName, err = g.bodyType(stub.Name+goIdentifier(status, true)+"Response", schema)
+
+				if err != nil {
+					return fmt.Errorf("response %s: %w", status, err)
+				}
+			}
+
+			entry.Schema = typeName
+
+			if !slices.Contains(g.data.Schemas, typeName) {
+				g.data.Schemas = append(g.data.Schemas, typeName)
+			}
+		}
+
+		stub.Responses = append(stub.Responses, entry)
+	}
+
+	return nil
+}
+
+// bodyType returns type of request or response schema, which must be struct or custom schema
+func (g *serverGenerator) bodyType(name string, schema *SpecSchema) (string, error) {
+	if schema.Ref != "" {
+		typeName := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		resolved := g.document.resolve(schema)
+
+		if resolved == nil {
+			return "", fmt.Errorf("unresolved reference (%s)", schema.Ref)
+		}
+
+		if len(resolved.Enum) == 0 {
+			return typeName, nil
+		}
+	}
+
+	name = uniqueName(name, g.names)
+
+	if g.isStruct(schema) {
+		return name, g.declareStruct(name, schema)
+	}
+
+	return g.declareCustom(name, schema), nil
+}
+
+// parameter declares type of parameter, implementing Spec, In and optionally Format, Style and Explode
+func (g *serverGenerator) parameter(operation string, param SpecParameter) (string, error) {
+	name := uniqueName(operation+goIdentifier(param.Name, true), g.names)
+	schema := g.document.resolve(param.Schema)
+
+	if schema == nil {
+		schema = &SpecSchema{Type: SpecTypes{string(JsonString)}}
+	}
+
+	declaration := serverType{
+		Name: name,
+		Param: &serverParam{
+			Name:        param.Name,
+			In:          param.In,
+			Required:    param.Required,
+			Description: param.Description,
+			Style:       param.Style,
+			Explode:     param.Explode,
+		},
+	}
+
+	if g.isStruct(schema) {
+		// deepObject parameters are flat structs
+		if err := g.declareStruct(name, schema); err != nil {
+			return "", err
+		}
+
+		g.data.Types[len(g.data.Types)-1].Param = declaration.Param
+
+		return name, nil
+	}
+
+	underlying, ok := g.native(schema)
+
+	if !ok {
+		return "", fmt.Errorf("unsupported schema, parameters must be scalars, arrays of scalars or flat objects")
+	}
+
+	declaration.Underlying = g.elementType(name, schema, underlying)
+
+	if len(schema.Enum) > 0 {
+		declaration.Enum = goLiteral(schema.Enum)
+	}
+
+	switch value := schema.Default.(type) {
+	case nil:
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			values = append(values, fmt.Sprint(item))
+		}
+		declaration.Default = strings.Join(values, ",")
+	default:
+		declaration.Default = fmt.Sprint(value)
+	}
+
+	if schema.Format != "" && nativeFormat(schema) != schema.Format {
+		declaration.Format = schema.Format
+	}
+
+	g.declare(declaration)
+
+	return name, nil
+}
+
+// jsonSchemaOf returns schema of json media type of content
+func jsonSchemaOf(content map[string]SpecMediaType) *SpecSchema {
+	if media, has := content["application/json"]; has {
+		return media.Schema
+	}
+
+	for _, mediaType := range slices.Sorted(maps.Keys(content)) {
+		if strings.HasSuffix(mediaType, "+json") {
+			return content[mediaType].Schema
+		}
+	}
+
+	return nil
+}
+
+// goLiteral returns go expression of value decoded from yaml
+func goLiteral(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "nil"
+	case map[string]any:
+		var b strings.Builder
+		b.WriteString("map[string]any{")
+		for i, key := range slices.Sorted(maps.Keys(value)) {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%q: %s", key, goLiteral(value[key]))
+		}
+		b.WriteString("}")
+		return b.String()
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, goLiteral(item))
+		}
+		return "[]any{" + strings.Join(items, ", ") + "}"
+	case string:
+		return strconv.Quote(value)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	return fmt.Sprintf("%#v", value)
+}
@@ -0,0 +1,201 @@
+package goapi_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petSpec = `
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema: {type: integer, format: int64}
+        - name: fields
+          in: query
+          schema: {type: array, items: {type: string, enum: [name, tags]}}
+      responses:
+        '200':
+          description: pet
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+        '409':
+          description: conflict
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        tags: {type: array, items: {type: string, format: uuid}}
+        owner:
+          type: object
+          properties:
+            email: {type: string, format: email}
+`
+
+// serverMain registers generated routes and writes document of api, main.go of regenerate
+const serverMain = `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+)
+
+func main() {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{Title: "pets", Version: "1"})
+
+	if err := RegisterRoutes(&api); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := api.WriteSpec(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`
+
+// regenerate generates server of spec, compiles and runs it with main, e.g. serverMain
+func regenerate(t *testing.T, spec string, main string) ([]byte, error) {
+	var buf bytes.Buffer
+	require.NoError(t, goapi.GenerateServer(&buf, parseSpec(t, spec), goapi.ServerOptions{Package: "main"}))
+
+	return goPackage(t, map[string][]byte{"server.go": buf.Bytes(), "main.go": []byte(main)}, "run")
+}
+
+func TestGenerateServer(t *testing.T) {
+	original := parseSpec(t, petSpec)
+
+	output, err := regenerate(t, petSpec, serverMain)
+	require.NoError(t, err)
+
+	regenerated, err := goapi.ParseSpec(output)
+	require.NoError(t, err)
+
+	diff := goapi.DiffSpecs(original, regenerated)
+
+	assert.False(t, diff.HasBreaking(), diff.Report())
+	assert.Equal(t, "getPet", regenerated.Paths["/pets/:petId"]["get"].OperationId)
+	assert.Equal(t, "uuid", regenerated.Components.Schemas["Pet"].Properties["tags"].Items.Format)
+}
+
+type IssueStatuses []IssueStatus
+
+func (IssueStatuses) Spec() goapi.Spec {
+	return goapi.Spec{Name: "statuses"}
+}
+
+func ListIssues(statuses IssueStatuses, status IssueStatus) (Issue, goapi.APIError) {
+	return Issue{Status: status}, nil
+}
+
+func TestGenerateServerEnumComponents(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{Title: "pets", Version: "1"})
+	api.EnumComponents = true
+	appRouter := api.Router()
+	appRouter.Get("/issues", ListIssues, goapi.RouteSpec{OperationId: "listIssues"})
+
+	var spec bytes.Buffer
+	require.NoError(t, api.WriteSpec(&spec))
+
+	main := strings.Replace(serverMain, "\n\n\tif err := RegisterRoutes", "\n\tapi.EnumComponents = true\n\n\tif err := RegisterRoutes", 1)
+
+	output, err := regenerate(t, spec.String(), main)
+	require.NoError(t, err)
+
+	regenerated, err := goapi.ParseSpec(output)
+	require.NoError(t, err)
+
+	diff := goapi.DiffSpecs(parseSpec(t, spec.String()), regenerated)
+
+	assert.False(t, diff.HasBreaking(), diff.Report())
+	assert.Equal(t, "#/components/schemas/IssueStatus", regenerated.Paths["/issues"]["get"].Parameters[0].Schema.Items.Ref)
+}
+
+func TestGenerateServerConflictingRoutes(t *testing.T) {
+	// only registration function reports errors, spec is not written
+	main := `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+)
+
+func main() {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+
+	if err := RegisterRoutes(&api); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`
+
+	_, err := regenerate(t, `
+paths:
+  /users/me:
+    get:
+      responses:
+        '200': {description: ok}
+  /users/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        '200': {description: ok}
+`, main)
+
+	assert.ErrorContains(t, err, "/users/:id", "Conflicting route not reported by registration function")
+}
+
+func TestGenerateServerUnsupported(t *testing.T) {
+	document := parseSpec(t, `
+paths:
+  /pets:
+    head:
+      responses:
+        '200': {description: ok}
+`)
+
+	assert.ErrorContains(t, goapi.GenerateServer(&bytes.Buffer{}, document, goapi.ServerOptions{}), "HEAD /pets: method is not supported")
+}
+
+type Owner struct {
+	Email string `json:"email" required:"true"`
+	Name  string `json:"name"`
+}
+
+func OwnerOf(owner Owner) (Owner, goapi.APIError) {
+	return owner, nil
+}
+
+func TestRequiredFields(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	appRouter.Post("/owners", OwnerOf, goapi.RouteSpec{})
+
+	document, err := api.SpecDocument()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"email"}, document.Components.Schemas["Owner"].Required)
+}
@@ -0,0 +1,493 @@
+package goapi
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type ChangeLevel int
+
+const (
+	ChangeInfo ChangeLevel = iota
+	ChangeNonBreaking
+	ChangeBreaking
+)
+
+func (l ChangeLevel) String() string {
+	switch l {
+	case ChangeBreaking:
+		return "breaking"
+	case ChangeNonBreaking:
+		return "non-breaking"
+	}
+
+	return "info"
+}
+
+// SpecChange is change between two documents, Operation is empty for changes of whole document
+type SpecChange struct {
+	Level     ChangeLevel
+	Operation string
+	Location  string
+	Message   string
+}
+
+func (c SpecChange) String() string {
+	var b strings.Builder
+
+	b.WriteString(c.Operation)
+
+	if c.Location != "" {
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(c.Location)
+	}
+
+	if b.Len() > 0 {
+		b.WriteString(": ")
+	}
+
+	b.WriteString(c.Message)
+
+	return b.String()
+}
+
+// SpecDiff are changes between two documents
+type SpecDiff []SpecChange
+
+// Breaking returns changes breaking existing clients
+func (d SpecDiff) Breaking() SpecDiff {
+	return d.level(ChangeBreaking)
+}
+
+func (d SpecDiff) HasBreaking() bool {
+	return len(d.Breaking()) > 0
+}
+
+func (d SpecDiff) level(level ChangeLevel) SpecDiff {
+	var out SpecDiff
+
+	for _, change := range d {
+		if change.Level == level {
+			out = append(out, change)
+		}
+	}
+
+	return out
+}
+
+// Report returns human readable report of changes grouped by level
+func (d SpecDiff) Report() string {
+	if len(d) == 0 {
+		return "No changes\n"
+	}
+
+	var b strings.Builder
+
+	sections := []struct {
+		title string
+		level ChangeLevel
+	}{
+		{"Breaking changes", ChangeBreaking},
+		{"Non-breaking changes", ChangeNonBreaking},
+		{"Informational changes", ChangeInfo},
+	}
+
+	for _, section := range sections {
+		changes := d.level(section.level)
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "%s (%d):\n", section.title, len(changes))
+
+		for _, change := range changes {
+			fmt.Fprintf(&b, "  %s\n", change)
+		}
+	}
+
+	return b.String()
+}
+
+// schemaContext is side of schema, compatibility rules of requests and responses are opposite
+type schemaContext int
+
+const (
+	requestContext schemaContext = iota
+	responseContext
+)
+
+type specDiffer struct {
+	old, new  *SpecDocument
+	operation string
+	changes   SpecDiff
+}
+
+func (d *specDiffer) report(level ChangeLevel, location string, format string, args ...any) {
+	d.changes = append(d.changes, SpecChange{
+		Level:     level,
+		Operation: d.operation,
+		Location:  location,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// DiffSpecs classifies changes from old to new document as breaking, non-breaking or informational
+func DiffSpecs(old, new *SpecDocument) SpecDiff {
+	d := &specDiffer{old: old, new: new}
+
+	oldPaths, newPaths := normalizePaths(old.Paths), normalizePaths(new.Paths)
+
+	for _, path := range sortedKeys(oldPaths, newPaths) {
+		oldItem, newItem := oldPaths[path], newPaths[path]
+
+		for _, method := range specMethods {
+			oldOperation, newOperation := oldItem[method], newItem[method]
+			d.operation = strings.ToUpper(method) + " " + path
+
+			switch {
+			case oldOperation == nil && newOperation == nil:
+			case newOperation == nil:
+				d.report(ChangeBreaking, "", "operation removed")
+			case oldOperation == nil:
+				d.report(ChangeNonBreaking, "", "operation added")
+			default:
+				d.compareOperation(oldOperation, newOperation)
+			}
+		}
+	}
+
+	d.operation = ""
+
+	for _, name := range sortedKeys(old.Components.Schemas, new.Components.Schemas) {
+		switch {
+		case new.Components.Schemas[name] == nil:
+			d.report(ChangeInfo, "schema "+name, "schema removed")
+		case old.Components.Schemas[name] == nil:
+			d.report(ChangeInfo, "schema "+name, "schema added")
+		}
+	}
+
+	return d.changes
+}
+
+// routeParam matches named and catch-all parameters of httprouter paths
+var routeParam = regexp.MustCompile(`/[:*]([^/]+)`)
+
+// normalizePaths keys path items by path with {name} templates, goapi documents routes as :name and *name
+func normalizePaths(paths map[string]SpecPathItem) map[string]SpecPathItem {
+	out := make(map[string]SpecPathItem, len(paths))
+
+	for path, item := range paths {
+		out[routeParam.ReplaceAllString(path, "/{$1}")] = item
+	}
+
+	return out
+}
+
+func (d *specDiffer) compareOperation(old, new *SpecOperation) {
+	if old.OperationId != new.OperationId {
+		d.report(ChangeBreaking, "", "operationId changed from (%s) to (%s), generated clients change", old.OperationId, new.OperationId)
+	}
+
+	if old.Summary != new.Summary || old.Description != new.Description {
+		d.report(ChangeInfo, "", "summary or description changed")
+	}
+
+	if !slices.Equal(old.Tags, new.Tags) {
+		d.report(ChangeInfo, "", "tags changed from %v to %v", old.Tags, new.Tags)
+	}
+
+	d.compareParameters(old.Parameters, new.Parameters)
+	d.compareRequestBody(old.RequestBody, new.RequestBody)
+	d.compareResponses(old.Responses, new.Responses)
+}
+
+func (d *specDiffer) compareParameters(old, new []SpecParameter) {
+	key := func(param SpecParameter) string {
+		return param.In + " " + param.Name
+	}
+
+	oldParams := make(map[string]SpecParameter, len(old))
+	for _, param := range old {
+		oldParams[key(param)] = param
+	}
+
+	newParams := make(map[string]SpecParameter, len(new))
+	for _, param := range new {
+		newParams[key(param)] = param
+	}
+
+	for _, name := range sortedKeys(oldParams, newParams) {
+		oldParam, inOld := oldParams[name]
+		newParam, inNew := newParams[name]
+		location := "parameter " + name
+
+		switch {
+		case !inNew:
+			d.report(ChangeNonBreaking, location, "parameter removed, value sent by clients is ignored")
+		case !inOld:
+			if newParam.Required {
+				d.report(ChangeBreaking, location, "required parameter added")
+			} else {
+				d.report(ChangeNonBreaking, location, "optional parameter added")
+			}
+		default:
+			if !oldParam.Required && newParam.Required {
+				d.report(ChangeBreaking, location, "parameter became required")
+			} else if oldParam.Required && !newParam.Required {
+				d.report(ChangeNonBreaking, location, "parameter became optional")
+			}
+
+			oldStyle, oldExplode := oldParam.serialization()
+			newStyle, newExplode := newParam.serialization()
+
+			if oldStyle != newStyle || oldExplode != newExplode {
+				d.report(ChangeBreaking, location, "serialization style changed")
+			}
+
+			if oldParam.Description != newParam.Description {
+				d.report(ChangeInfo, location, "description changed")
+			}
+
+			d.compareSchema(requestContext, location, oldParam.Schema, newParam.Schema, nil)
+		}
+	}
+}
+
+// serialization returns style and explode of parameter, defaults follow openapi spec
+func (p SpecParameter) serialization() (string, bool) {
+	style := p.Style
+
+	if style == "" {
+		style = string(StyleForm)
+		if p.In == string(ParamPath) || p.In == string(ParamHeader) {
+			style = string(StyleSimple)
+		}
+	}
+
+	if p.Explode != nil {
+		return style, *p.Explode
+	}
+
+	return style, style == string(StyleForm)
+}
+
+func (d *specDiffer) compareRequestBody(old, new *SpecRequestBody) {
+	switch {
+	case old == nil && new == nil:
+		return
+	case new == nil:
+		d.report(ChangeBreaking, "request body", "request body removed")
+		return
+	case old == nil:
+		if new.Required {
+			d.report(ChangeBreaking, "request body", "required request body added")
+		} else {
+			d.report(ChangeNonBreaking, "request body", "optional request body added")
+		}
+		return
+	}
+
+	if !old.Required && new.Required {
+		d.report(ChangeBreaking, "request body", "request body became required")
+	}
+
+	d.compareContent(requestContext, "request body", old.Content, new.Content)
+}
+
+func (d *specDiffer) compareResponses(old, new map[string]*SpecResponse) {
+	for _, status := range sortedKeys(old, new) {
+		oldResponse, newResponse := old[status], new[status]
+		location := "response " + status
+
+		switch {
+		case newResponse == nil:
+			d.report(ChangeBreaking, location, "response status removed")
+		case oldResponse == nil:
+			d.report(ChangeNonBreaking, location, "response status added")
+		default:
+			for _, header := range sortedKeys(oldResponse.Headers, newResponse.Headers) {
+				_, inOld := oldResponse.Headers[header]
+				_, inNew := newResponse.Headers[header]
+
+				if !inNew {
+					d.report(ChangeBreaking, location+" header "+header, "response header removed")
+				} else if !inOld {
+					d.report(ChangeNonBreaking, location+" header "+header, "response header added")
+				}
+			}
+
+			d.compareContent(responseContext, location, oldResponse.Content, newResponse.Content)
+		}
+	}
+}
+
+func (d *specDiffer) compareContent(context schemaContext, location string, old, new map[string]SpecMediaType) {
+	for _, mediaType := range sortedKeys(old, new) {
+		oldMedia, inOld := old[mediaType]
+		newMedia, inNew := new[mediaType]
+
+		switch {
+		case !inNew:
+			d.report(ChangeBreaking, location+" "+mediaType, "media type removed")
+		case !inOld:
+			d.report(ChangeNonBreaking, location+" "+mediaType, "media type added")
+		default:
+			d.compareSchema(context, location, oldMedia.Schema, newMedia.Schema, nil)
+		}
+	}
+}
+
+// schemaPair guards recursion of self referencing schemas
+type schemaPair struct {
+	old, new *SpecSchema
+}
+
+// compareSchema compares schemas in context, narrowing of requests and widening of responses break clients
+func (d *specDiffer) compareSchema(context schemaContext, location string, old, new *SpecSchema, seen map[schemaPair]bool) {
+	old, new = d.old.resolve(old), d.new.resolve(new)
+
+	if old == nil || new == nil {
+		return
+	}
+
+	if seen == nil {
+		seen = map[schemaPair]bool{}
+	}
+
+	if seen[schemaPair{old, new}] {
+		return
+	}
+
+	seen[schemaPair{old, new}] = true
+
+	// level of change that accepts fewer values than before
+	narrowing, widening := ChangeBreaking, ChangeNonBreaking
+	if context == responseContext {
+		narrowing, widening = ChangeNonBreaking, ChangeBreaking
+	}
+
+	if !slices.Equal(old.Type, new.Type) {
+		switch {
+		case typesWiden(old.Type, new.Type):
+			d.report(widening, location, "type widened from (%s) to (%s)", old.Type, new.Type)
+		case typesWiden(new.Type, old.Type):
+			d.report(narrowing, location, "type narrowed from (%s) to (%s)", old.Type, new.Type)
+		default:
+			d.report(ChangeBreaking, location, "type changed from (%s) to (%s)", old.Type, new.Type)
+		}
+	}
+
+	if old.Format != new.Format {
+		switch {
+		case old.Format == "":
+			d.report(narrowing, location, "format (%s) added", new.Format)
+		case new.Format == "":
+			d.report(widening, location, "format (%s) removed", old.Format)
+		default:
+			d.report(ChangeBreaking, location, "format changed from (%s) to (%s)", old.Format, new.Format)
+		}
+	}
+
+	d.compareEnum(location, old.Enum, new.Enum, narrowing, widening)
+
+	if !reflect.DeepEqual(old.Default, new.Default) {
+		d.report(ChangeInfo, location, "default changed from (%v) to (%v)", old.Default, new.Default)
+	}
+
+	for _, name := range sortedKeys(old.Properties, new.Properties) {
+		oldProperty, newProperty := old.Properties[name], new.Properties[name]
+		propertyLocation := location + " ." + name
+
+		switch {
+		case newProperty == nil:
+			d.report(ChangeBreaking, propertyLocation, "field removed")
+		case oldProperty == nil:
+			if context == requestContext && slices.Contains(new.Required, name) {
+				d.report(ChangeBreaking, propertyLocation, "required field added")
+			} else {
+				d.report(ChangeNonBreaking, propertyLocation, "field added")
+			}
+		default:
+			wasRequired, isRequired := slices.Contains(old.Required, name), slices.Contains(new.Required, name)
+
+			switch {
+			case !wasRequired && isRequired:
+				d.report(narrowing, propertyLocation, "field became required")
+			case wasRequired && !isRequired:
+				d.report(widening, propertyLocation, "field became optional")
+			}
+
+			d.compareSchema(context, propertyLocation, oldProperty, newProperty, seen)
+		}
+	}
+
+	d.compareSchema(context, location+"[]", old.Items, new.Items, seen)
+	d.compareSchema(context, location+"{}", old.AdditionalProperties, new.AdditionalProperties, seen)
+}
+
+func (d *specDiffer) compareEnum(location string, old, new []any, narrowing, widening ChangeLevel) {
+	switch {
+	case len(old) == 0 && len(new) == 0:
+		return
+	case len(old) == 0:
+		d.report(narrowing, location, "enum %v added", new)
+		return
+	case len(new) == 0:
+		d.report(widening, location, "enum removed")
+		return
+	}
+
+	contains := func(values []any, value any) bool {
+		return slices.ContainsFunc(values, func(el any) bool { return reflect.DeepEqual(el, value) })
+	}
+
+	for _, value := range old {
+		if !contains(new, value) {
+			d.report(narrowing, location, "enum value (%v) removed", value)
+		}
+	}
+
+	for _, value := range new {
+		if !contains(old, value) {
+			d.report(widening, location, "enum value (%v) added", value)
+		}
+	}
+}
+
+// typesWiden reports if to accepts every value of from
+func typesWiden(from, to SpecTypes) bool {
+	if len(from) == 0 || len(to) == 0 {
+		return len(to) == 0
+	}
+
+	for _, t := range from {
+		if !slices.Contains(to, t) && !(t == string(JsonInteger) && slices.Contains(to, string(JsonNumber))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortedKeys returns union of keys of maps, sorted
+func sortedKeys[V any](a, b map[string]V) []string {
+	keys := slices.Collect(maps.Keys(a))
+
+	for key := range b {
+		if _, has := a[key]; !has {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,182 @@
+package goapi_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseSpec = `
+paths:
+  /users:
+    post:
+      operationId: createUser
+      parameters:
+        - name: dryRun
+          in: query
+          schema: {type: boolean}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/User'}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+components:
+  schemas:
+    User:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        age: {type: integer}
+        role: {type: string, enum: [admin, user]}
+`
+
+func parseSpec(t *testing.T, data string) *goapi.SpecDocument {
+	document, err := goapi.ParseSpec([]byte(data))
+	require.NoError(t, err)
+	return document
+}
+
+func TestDiffSpecs(t *testing.T) {
+	old := parseSpec(t, baseSpec)
+
+	assert.Empty(t, goapi.DiffSpecs(old, old))
+	assert.Equal(t, "No changes\n", goapi.DiffSpecs(old, old).Report())
+
+	tests := []struct {
+		name     string
+		schema   string
+		level    goapi.ChangeLevel
+		location string
+	}{
+		{"removed field", `    User:
+      type: object
+      properties:
+        name: {type: string}
+        role: {type: string, enum: [admin, user]}
+`, goapi.ChangeBreaking, "request body .age"},
+		{"new enum value breaks response", `    User:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        age: {type: integer}
+        role: {type: string, enum: [admin, user, guest]}
+`, goapi.ChangeBreaking, "response 200 .role"},
+		{"narrowed type", `    User:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        age: {type: integer, format: int32}
+        role: {type: string, enum: [admin, user]}
+`, goapi.ChangeBreaking, "request body .age"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			new := parseSpec(t, strings.Replace(baseSpec, userSchema, tt.schema, 1))
+
+			found := false
+			for _, change := range goapi.DiffSpecs(old, new) {
+				if change.Location == tt.location {
+					assert.Equal(t, tt.level, change.Level, change.String())
+					found = true
+				}
+			}
+			assert.True(t, found)
+		})
+	}
+}
+
+const userSchema = `    User:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        age: {type: integer}
+        role: {type: string, enum: [admin, user]}
+`
+
+func TestDiffSpecsParameters(t *testing.T) {
+	old := parseSpec(t, baseSpec)
+	new := parseSpec(t, baseSpec)
+
+	operation := new.Paths["/users"]["post"]
+	operation.Parameters[0].Required = true
+	operation.Parameters = append(operation.Parameters, goapi.SpecParameter{Name: "trace", In: "header"})
+	operation.Summary = "Create user"
+	delete(operation.Responses, "200")
+	operation.Responses["201"] = &goapi.SpecResponse{Description: "created"}
+
+	diff := goapi.DiffSpecs(old, new)
+
+	assert.Equal(t, goapi.SpecDiff{
+		{Level: goapi.ChangeInfo, Operation: "POST /users", Message: "summary or description changed"},
+		{Level: goapi.ChangeNonBreaking, Operation: "POST /users", Location: "parameter header trace", Message: "optional parameter added"},
+		{Level: goapi.ChangeBreaking, Operation: "POST /users", Location: "parameter query dryRun", Message: "parameter became required"},
+		{Level: goapi.ChangeBreaking, Operation: "POST /users", Location: "response 200", Message: "response status removed"},
+		{Level: goapi.ChangeNonBreaking, Operation: "POST /users", Location: "response 201", Message: "response status added"},
+	}, diff)
+
+	assert.True(t, diff.HasBreaking())
+	assert.Len(t, diff.Breaking(), 2)
+	assert.Equal(t, `Breaking changes (2):
+  POST /users parameter query dryRun: parameter became required
+  POST /users response 200: response status removed
+Non-breaking changes (2):
+  POST /users parameter header trace: optional parameter added
+  POST /users response 201: response status added
+Informational changes (1):
+  POST /users: summary or description changed
+`, diff.Report())
+}
+
+func TestDiffSpecsRoutePaths(t *testing.T) {
+	old := parseSpec(t, `
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+        - {name: tags, in: query, schema: {type: array, items: {type: string}}}
+  /files/{filepath}:
+    get:
+      responses: {"200": {description: file}}
+`)
+	new := parseSpec(t, `
+paths:
+  /pets/:id:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, style: simple, explode: false, schema: {type: integer}}
+        - {name: tags, in: query, style: form, explode: true, schema: {type: array, items: {type: string}}}
+  /files/*filepath:
+    get:
+      responses: {"200": {description: file}}
+`)
+
+	assert.Empty(t, goapi.DiffSpecs(old, new))
+}
+
+func TestAPISpecDocument(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{OperationId: "calculate"})
+
+	old, err := api.SpecDocument()
+	require.NoError(t, err)
+
+	assert.Equal(t, "calculate", old.Paths["/calculate"]["post"].OperationId)
+	assert.Empty(t, goapi.DiffSpecs(old, old))
+}
@@ -0,0 +1,184 @@
+package goapi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecDocument is model of openapi document, limited to parts relevant for compatibility of clients
+type SpecDocument struct {
+	Paths      map[string]SpecPathItem `yaml:"paths"`
+	Components struct {
+		Schemas map[string]*SpecSchema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+// SpecPathItem holds operations of path by lower case method
+type SpecPathItem map[string]*SpecOperation
+
+var specMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+func (p *SpecPathItem) UnmarshalYAML(node *yaml.Node) error {
+	var raw map[string]yaml.Node
+
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	*p = make(SpecPathItem)
+
+	for _, method := range specMethods {
+		value, has := raw[method]
+
+		if !has {
+			continue
+		}
+
+		var operation SpecOperation
+
+		if err := value.Decode(&operation); err != nil {
+			return fmt.Errorf("%s: %w", method, err)
+		}
+
+		(*p)[method] = &operation
+	}
+
+	return nil
+}
+
+type SpecOperation struct {
+	OperationId string                   `yaml:"operationId"`
+	Summary     string                   `yaml:"summary"`
+	Description string                   `yaml:"description"`
+	Tags        []string                 `yaml:"tags"`
+	Parameters  []SpecParameter          `yaml:"parameters"`
+	RequestBody *SpecRequestBody         `yaml:"requestBody"`
+	Responses   map[string]*SpecResponse `yaml:"responses"`
+}
+
+type SpecParameter struct {
+	Name        string      `yaml:"name"`
+	In          string      `yaml:"in"`
+	Description string      `yaml:"description"`
+	Required    bool        `yaml:"required"`
+	Style       string      `yaml:"style"`
+	Explode     *bool       `yaml:"explode"`
+	Schema      *SpecSchema `yaml:"schema"`
+}
+
+type SpecRequestBody struct {
+	Required bool                     `yaml:"required"`
+	Content  map[string]SpecMediaType `yaml:"content"`
+}
+
+type SpecMediaType struct {
+	Schema  *SpecSchema `yaml:"schema"`
+	Example any         `yaml:"example"`
+}
+
+type SpecResponse struct {
+	Description string                   `yaml:"description"`
+	Headers     map[string]SpecHeader    `yaml:"headers"`
+	Content     map[string]SpecMediaType `yaml:"content"`
+}
+
+type SpecHeader struct {
+	Schema *SpecSchema `yaml:"schema"`
+}
+
+type SpecSchema struct {
+	Ref                  string                 `yaml:"$ref"`
+	Type                 SpecTypes              `yaml:"type"`
+	Format               string                 `yaml:"format"`
+	Enum                 []any                  `yaml:"enum"`
+	Default              any                    `yaml:"default"`
+	Example              any                    `yaml:"example"`
+	Items                *SpecSchema            `yaml:"items"`
+	Properties           map[string]*SpecSchema `yaml:"properties"`
+	Required             []string               `yaml:"required"`
+	AdditionalProperties *SpecSchema            `yaml:"additionalProperties"`
+	OneOf                []*SpecSchema          `yaml:"oneOf"`
+
+	// raw is schema as decoded from document, including keywords not modeled above
+	raw map[string]any
+}
+
+func (s *SpecSchema) UnmarshalYAML(node *yaml.Node) error {
+	// boolean schemas, e.g. additionalProperties: true, accept any value
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!bool" {
+		*s = SpecSchema{}
+		return nil
+	}
+
+	type plain SpecSchema
+
+	if err := node.Decode((*plain)(s)); err != nil {
+		return err
+	}
+
+	return node.Decode(&s.raw)
+}
+
+// SpecTypes is type of schema, a single type or list of types
+type SpecTypes []string
+
+func (t *SpecTypes) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = SpecTypes{node.Value}
+		return nil
+	}
+
+	var types []string
+
+	if err := node.Decode(&types); err != nil {
+		return err
+	}
+
+	*t = types
+
+	return nil
+}
+
+func (t SpecTypes) String() string {
+	return strings.Join(t, "|")
+}
+
+// ParseSpec parses openapi document in yaml or json
+func ParseSpec(data []byte) (*SpecDocument, error) {
+	var document SpecDocument
+
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+
+	return &document, nil
+}
+
+// SpecDocument returns model of document generated for api
+func (api *API) SpecDocument() (*SpecDocument, error) {
+	var buf bytes.Buffer
+
+	if err := api.WriteSpec(&buf); err != nil {
+		return nil, err
+	}
+
+	return ParseSpec(buf.Bytes())
+}
+
+// resolve follows reference to component schema
+func (d *SpecDocument) resolve(schema *SpecSchema) *SpecSchema {
+	for depth := 0; schema != nil && schema.Ref != "" && depth < 32; depth++ {
+		name, found := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+
+		if !found {
+			return schema
+		}
+
+		schema = d.Components.Schemas[name]
+	}
+
+	return schema
+}
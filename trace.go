@@ -0,0 +1,343 @@
+package goapi
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Span is timed operation of request, methods are safe to call on nil span
+type Span struct {
+	TraceID  TraceID
+	SpanID   SpanID
+	ParentID SpanID
+	Name     string
+	Start    time.Time
+	End      time.Time
+	// Flags are W3C trace flags propagated from parent
+	Flags byte
+
+	mu         sync.Mutex
+	attributes map[string]any
+	err        string
+	exporter   SpanExporter
+}
+
+// SpanExporter receives spans when they end
+type SpanExporter interface {
+	ExportSpan(span *Span)
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns innermost span of context, nil when tracing is disabled
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// StartSpan starts child of span of ctx, it returns nil span when ctx has no span
+func StartSpan(ctx context.Context, name string) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+
+	if parent == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		TraceID:  parent.TraceID,
+		SpanID:   newSpanID(),
+		ParentID: parent.SpanID,
+		Name:     name,
+		Start:    time.Now(),
+		Flags:    parent.Flags,
+		exporter: parent.exporter,
+	}
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *Span) SetAttribute(key string, value any) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.attributes == nil {
+		s.attributes = make(map[string]any)
+	}
+
+	s.attributes[key] = value
+}
+
+// Attributes returns copy of attributes of span
+func (s *Span) Attributes() map[string]any {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make(map[string]any, len(s.attributes))
+	for key, value := range s.attributes {
+		out[key] = value
+	}
+
+	return out
+}
+
+// RecordError marks span as failed, value is error or error handler input
+func (s *Span) RecordError(value any) {
+	if s == nil {
+		return
+	}
+
+	detail := fmt.Sprint(value)
+	if apiError, ok := value.(APIError); ok {
+		detail = apiError.Detail
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = detail
+}
+
+// Err returns error recorded by span
+func (s *Span) Err() string {
+	if s == nil {
+		return ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// Finish ends span and exports it
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+
+	s.End = time.Now()
+	s.exporter.ExportSpan(s)
+}
+
+// TraceParent returns W3C traceparent header of span, to propagate trace to outgoing requests
+func (s *Span) TraceParent() string {
+	if s == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("00-%s-%s-%02x", s.TraceID, s.SpanID, s.Flags)
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for id == (SpanID{}) {
+		putUint64(id[:], rand.Uint64())
+	}
+	return id
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for id == (TraceID{}) {
+		putUint64(id[:8], rand.Uint64())
+		putUint64(id[8:], rand.Uint64())
+	}
+	return id
+}
+
+func putUint64(out []byte, value uint64) {
+	for i := range 8 {
+		out[i] = byte(value >> (8 * i))
+	}
+}
+
+// parseTraceParent parses W3C traceparent header, reports false if it is invalid
+func parseTraceParent(header string) (TraceID, SpanID, byte, bool) {
+	var traceID TraceID
+	var parentID SpanID
+	var flags [1]byte
+
+	parts := strings.Split(header, "-")
+
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return traceID, parentID, 0, false
+	}
+
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return traceID, parentID, 0, false
+	}
+
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil || traceID == (TraceID{}) {
+		return traceID, parentID, 0, false
+	}
+
+	if _, err := hex.Decode(parentID[:], []byte(parts[2])); err != nil || parentID == (SpanID{}) {
+		return traceID, parentID, 0, false
+	}
+
+	if _, err := hex.Decode(flags[:], []byte(parts[3])); err != nil {
+		return traceID, parentID, 0, false
+	}
+
+	return traceID, parentID, flags[0], true
+}
+
+// Tracing starts span per request, continuing trace of traceparent header, and exports spans by exporter
+func (api *API) Tracing(exporter SpanExporter) {
+	api.exporter = exporter
+}
+
+// withTracing starts request span of route, when tracing is enabled
+func withTracing(api *API, path string, endpointMethod *EndpointMethod, handle httprouter.Handle) httprouter.Handle {
+	method := strings.ToUpper(string(endpointMethod.Method))
+
+	name := endpointMethod.OperationId
+	if name == "" {
+		name = method + " " + path
+	}
+
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		exporter := api.exporter
+
+		if exporter == nil {
+			handle(w, req, params)
+			return
+		}
+
+		span := &Span{
+			SpanID:   newSpanID(),
+			Name:     name,
+			Start:    time.Now(),
+			Flags:    1,
+			exporter: exporter,
+		}
+
+		if traceID, parentID, flags, ok := parseTraceParent(req.Header.Get("traceparent")); ok {
+			span.TraceID, span.ParentID, span.Flags = traceID, parentID, flags
+		} else {
+			span.TraceID = newTraceID()
+		}
+
+		span.SetAttribute("http.method", method)
+		span.SetAttribute("http.route", path)
+
+		req = req.WithContext(context.WithValue(req.Context(), spanKey{}, span))
+		recorder := &statusWriter{ResponseWriter: w}
+
+		defer func() {
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			span.SetAttribute("http.status_code", status)
+			span.Finish()
+		}()
+
+		handle(recorder, req, params)
+	}
+}
+
+// MemoryExporter keeps exported spans in memory, for tests
+type MemoryExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+func (e *MemoryExporter) ExportSpan(span *Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns exported spans in order they ended
+func (e *MemoryExporter) Spans() []*Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]*Span(nil), e.spans...)
+}
+
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = nil
+}
+
+// WriterExporter writes exported spans as json lines, e.g. to os.Stdout
+type WriterExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	return &WriterExporter{w: w}
+}
+
+func (e *WriterExporter) ExportSpan(span *Span) {
+	record := struct {
+		TraceID    string         `json:"traceId"`
+		SpanID     string         `json:"spanId"`
+		ParentID   string         `json:"parentId,omitempty"`
+		Name       string         `json:"name"`
+		Start      time.Time      `json:"start"`
+		Duration   time.Duration  `json:"durationNs"`
+		Attributes map[string]any `json:"attributes,omitempty"`
+		Error      string         `json:"error,omitempty"`
+	}{
+		TraceID:    span.TraceID.String(),
+		SpanID:     span.SpanID.String(),
+		Name:       span.Name,
+		Start:      span.Start,
+		Duration:   span.End.Sub(span.Start),
+		Attributes: span.Attributes(),
+		Error:      span.Err(),
+	}
+
+	if span.ParentID != (SpanID{}) {
+		record.ParentID = span.ParentID.String()
+	}
+
+	data, err := json.Marshal(record)
+
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.w.Write(append(data, '\n'))
+}
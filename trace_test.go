@@ -0,0 +1,60 @@
+package goapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracing(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	var traceParent string
+
+	appRouter.Post("/calculate", func(ctx context.Context, calc Calculation) (Result, goapi.APIError) {
+		traceParent = goapi.SpanFromContext(ctx).TraceParent()
+		return Calculate(calc)
+	}, goapi.RouteSpec{OperationId: "calculate"})
+
+	exporter := &goapi.MemoryExporter{}
+	api.Tracing(exporter)
+
+	req := httptest.NewRequest("POST", "/calculate", strings.NewReader(`{"left": 1, "right": 2}`))
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	api.Handler().ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.Spans()
+	names := make([]string, 0, len(spans))
+
+	for _, span := range spans {
+		names = append(names, span.Name)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID.String(), "Trace not propagated")
+	}
+
+	assert.Equal(t, []string{"decode", "bind", "call", "calculate"}, names)
+
+	request := spans[len(spans)-1]
+	assert.Equal(t, "00f067aa0ba902b7", request.ParentID.String())
+	assert.Equal(t, http.StatusOK, request.Attributes()["http.status_code"])
+	assert.Equal(t, request.SpanID, spans[2].ParentID)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+spans[2].SpanID.String()+"-01", traceParent)
+
+	exporter.Reset()
+	api.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/calculate", strings.NewReader(`{"left": 1`)))
+
+	spans = exporter.Spans()
+	request = spans[len(spans)-1]
+
+	assert.Equal(t, "error", spans[0].Name)
+	assert.NotEmpty(t, request.Err(), "Error not recorded")
+	assert.Equal(t, http.StatusBadRequest, request.Attributes()["http.status_code"])
+	assert.NotEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", request.TraceID.String())
+}
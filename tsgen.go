@@ -0,0 +1,357 @@
+package goapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+type tsField struct {
+	Name     string
+	Optional bool
+	Type     string
+}
+
+// tsDeclaration is interface when it has fields, type alias otherwise
+type tsDeclaration struct {
+	Name   string
+	Type   string
+	Fields []tsField
+}
+
+type tsTypes struct {
+	Enums        []tsDeclaration
+	Declarations []tsDeclaration
+	ErrorType    string
+}
+
+type tsParam struct {
+	Key      string
+	Name     string
+	In       ParamIn
+	Style    ParamStyle
+	Explode  bool
+	Required bool
+	Type     string
+}
+
+type tsOperation struct {
+	Name         string
+	ParamsType   string
+	Method       string
+	Path         string
+	Summary      string
+	Params       []tsParam
+	Body         string
+	ResponseType string
+}
+
+type tsClient struct {
+	Operations []tsOperation
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsKey returns property key, quoted when it is not identifier
+func tsKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+
+	return fmt.Sprintf("%q", name)
+}
+
+// tsTypeOf returns typescript type of meta, qualify prefixes names of declared types
+func tsTypeOf(meta Meta, qualify string) string {
+	if meta.Custom != nil {
+		return tsTypeOfSchema(meta.Custom)
+	}
+
+	if meta.enumType != nil {
+		return qualify + meta.enumType.Name()
+	}
+
+	if enum, has := meta.Rest["enum"]; has {
+		if union := tsEnumUnion(enum); union != "" {
+			return union
+		}
+	}
+
+	switch meta.Type {
+	case JsonString:
+		return "string"
+	case JsonInteger, JsonNumber:
+		return "number"
+	case JsonBoolean:
+		return "boolean"
+	case JsonNull:
+		return "null"
+	case JsonArray:
+		if meta.Items == nil {
+			return "unknown[]"
+		}
+		return tsArray(tsTypeOf(*meta.Items, qualify))
+	case JsonObject:
+		if meta.Properties != nil {
+			fields := make([]string, 0, len(meta.Properties))
+			for _, prop := range meta.Properties {
+				fields = append(fields, fmt.Sprintf("%s?: %s", tsKey(prop.Name), tsTypeOf(prop.Meta, qualify)))
+			}
+			return "{ " + strings.Join(fields, "; ") + " }"
+		}
+		if meta.AdditionalProperties != nil {
+			return "Record<string, " + tsTypeOf(*meta.AdditionalProperties, qualify) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+
+	return "unknown"
+}
+
+// tsTypeOfSchema returns typescript type of json schema provided by SchemaProvider
+func tsTypeOfSchema(schema map[string]any) string {
+	if enum, ok := schema["enum"].([]any); ok {
+		data, err := json.Marshal(enum)
+		if err == nil {
+			if union := tsEnumUnion(string(data)); union != "" {
+				return union
+			}
+		}
+	}
+
+	switch schema["type"] {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "null":
+		return "null"
+	case "array":
+		if items, ok := schema["items"].(map[string]any); ok {
+			return tsArray(tsTypeOfSchema(items))
+		}
+		return "unknown[]"
+	case "object":
+		if properties, ok := schema["properties"].(map[string]any); ok {
+			required, _ := schema["required"].([]any)
+
+			names := make([]string, 0, len(properties))
+			for name := range properties {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+
+			fields := make([]string, 0, len(names))
+			for _, name := range names {
+				property, _ := properties[name].(map[string]any)
+				optional := "?"
+				if slices.Contains(required, any(name)) {
+					optional = ""
+				}
+				fields = append(fields, fmt.Sprintf("%s%s: %s", tsKey(name), optional, tsTypeOfSchema(property)))
+			}
+			return "{ " + strings.Join(fields, "; ") + " }"
+		}
+		if values, ok := schema["additionalProperties"].(map[string]any); ok {
+			return "Record<string, " + tsTypeOfSchema(values) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+
+	return "unknown"
+}
+
+// tsEnumUnion returns union of literals of enum encoded as json array
+func tsEnumUnion(enum string) string {
+	var values []json.RawMessage
+
+	if err := json.Unmarshal([]byte(enum), &values); err != nil || len(values) == 0 {
+		return ""
+	}
+
+	literals := make([]string, 0, len(values))
+	for _, value := range values {
+		literals = append(literals, string(value))
+	}
+
+	return strings.Join(literals, " | ")
+}
+
+func tsArray(item string) string {
+	if strings.ContainsAny(item, " |") && !strings.HasPrefix(item, "{") && !strings.HasPrefix(item, "Record<") {
+		return "(" + item + ")[]"
+	}
+
+	return item + "[]"
+}
+
+// tsFields returns fields of schema, optional fields are omitted when empty and pointers are nullable
+func tsFields(schema Schema) []tsField {
+	fields := make([]tsField, 0, len(schema.Properties))
+
+	for _, prop := range schema.Properties {
+		field := tsField{Name: tsKey(prop.Name), Type: tsTypeOf(prop.Meta, "")}
+
+		if schema.sourceType != nil && schema.sourceType.Kind() == reflect.Struct {
+			for i := 0; i < schema.sourceType.NumField(); i++ {
+				structField := schema.sourceType.Field(i)
+
+				if name, ok := jsonFieldName(structField); !ok || name != prop.Name {
+					continue
+				}
+
+				options := strings.Split(structField.Tag.Get("json"), ",")[1:]
+				field.Optional = slices.Contains(options, "omitempty") || slices.Contains(options, "omitzero")
+
+				if kind := structField.Type.Kind(); kind == reflect.Pointer || kind == reflect.Interface {
+					field.Type += " | null"
+				}
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func newTSTypes(api *API) tsTypes {
+	out := tsTypes{ErrorType: "unknown"}
+
+	if api.errorScheme != "" {
+		out.ErrorType = api.errorScheme
+	}
+
+	for _, Type := range collectAPIEnums(api) {
+		meta, err := resolveMeta(Type)
+
+		if err != nil {
+			continue
+		}
+
+		meta.enumType = nil
+
+		out.Enums = append(out.Enums, tsDeclaration{Name: Type.Name(), Type: tsTypeOf(meta, "")})
+	}
+
+	for _, schema := range api.Schemas {
+		declaration := tsDeclaration{Name: schema.Name}
+
+		if schema.Meta != nil {
+			meta := *schema.Meta
+			meta.enumType = nil
+			declaration.Type = tsTypeOf(meta, "")
+		} else if fields := tsFields(schema); len(fields) > 0 {
+			declaration.Fields = fields
+		} else {
+			declaration.Type = "Record<string, never>"
+		}
+
+		out.Declarations = append(out.Declarations, declaration)
+	}
+
+	for _, group := range api.SchemeGroups {
+		declaration := tsDeclaration{Name: group.Name}
+
+		for _, scheme := range group.Schemes {
+			declaration.Fields = append(declaration.Fields, tsField{Name: schemePrefix(scheme), Optional: true, Type: scheme})
+		}
+
+		out.Declarations = append(out.Declarations, declaration)
+	}
+
+	return out
+}
+
+func newTSClient(api *API) tsClient {
+	var out tsClient
+
+	names := map[string]bool{"Request": true, "Format": true}
+
+	for _, entry := range api.Endpoints {
+		for _, method := range entry.Methods {
+			exported := uniqueName(operationName(entry.Path, method), names)
+			name := string(unicode.ToLower(rune(exported[0]))) + exported[1:]
+
+			operation := tsOperation{
+				Name:         name,
+				ParamsType:   exported + "Params",
+				Method:       strings.ToUpper(string(method.Method)),
+				Path:         entry.Path,
+				Summary:      method.Summary,
+				ResponseType: "void",
+			}
+
+			for _, param := range method.Parameters {
+				operation.Params = append(operation.Params, tsParam{
+					Key:      tsKey(param.Name),
+					Name:     param.Name,
+					In:       param.In,
+					Style:    param.Style,
+					Explode:  param.Explode,
+					Required: param.Required,
+					Type:     tsTypeOf(param.Meta, "types."),
+				})
+			}
+
+			if method.RequestBody != "" {
+				operation.Body = "types." + method.RequestBody
+			}
+
+			if method.ResponseType != "" {
+				operation.ResponseType = "types." + method.ResponseType
+			}
+
+			out.Operations = append(out.Operations, operation)
+		}
+	}
+
+	return out
+}
+
+// GenerateTypeScript writes types.ts with declarations of schemas and client.ts with fetch function
+// per operation into dir
+func (api *API) GenerateTypeScript(dir string) error {
+	files := []struct {
+		name string
+		data any
+	}{
+		{"types.ts", newTSTypes(api)},
+		{"client.ts", newTSClient(api)},
+	}
+
+	for _, file := range files {
+		tmpl, err := template.ParseFS(templateFS, file.name+".tmpl")
+
+		if err != nil {
+			return err
+		}
+
+		out, err := os.Create(filepath.Join(dir, file.name))
+
+		if err != nil {
+			return err
+		}
+
+		err = tmpl.Execute(out, file.data)
+
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
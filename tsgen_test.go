@@ -0,0 +1,46 @@
+package goapi_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func CalculatePage(calc Calculation, page Page) (Result, goapi.APIError) {
+	return Calculate(calc)
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	api := goapi.NewAPI(httprouter.New(), goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	appRouter.Post("/calculate", Calculate, goapi.RouteSpec{})
+	appRouter.Get("/users/:id", GetUser, goapi.RouteSpec{OperationId: "find-user"})
+	appRouter.Post("/issues", func(issue Issue, status IssueStatus) (Issue, goapi.APIError) {
+		return issue, nil
+	}, goapi.RouteSpec{OperationId: "createIssue"})
+	appRouter.Post("/paginate", CalculatePage, goapi.RouteSpec{})
+
+	dir := t.TempDir()
+	assert.NoError(t, api.GenerateTypeScript(dir))
+
+	types, err := os.ReadFile(filepath.Join(dir, "types.ts"))
+	assert.NoError(t, err)
+
+	client, err := os.ReadFile(filepath.Join(dir, "client.ts"))
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(types), "export interface Calculation {\n  left: number;\n  right: number;\n}")
+	assert.Contains(t, string(types), `export type IssueStatus = "open" | "closed";`)
+	assert.Contains(t, string(types), "status: IssueStatus;")
+	assert.Contains(t, string(types), "export type ApiErrorBody = DefaultErrorType;")
+	assert.Contains(t, string(types), "export interface CalculatePage {\n  calculation?: Calculation;\n  page?: Page;\n}")
+
+	assert.Contains(t, string(client), "export async function calculate(\n  options: ClientOptions,\n  body: types.Calculation,\n): Promise<types.Result>")
+	assert.Contains(t, string(client), "export interface FindUserParams {\n  id: number;")
+	assert.Contains(t, string(client), "status?: types.IssueStatus;")
+}
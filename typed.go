@@ -0,0 +1,187 @@
+package goapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Handler is endpoint with typed input and output.
+//
+// Fields of In are bound like arguments of reflection based endpoints: parameter types,
+// body schemas and Response. Fields of plain types are named and placed by one of
+// path, query, header or cookie tags, with optional required and description tags.
+// Untagged fields of plain types are query or path parameters named after the field.
+type Handler[In, Out any] = func(ctx context.Context, in In) (Out, error)
+
+var paramTags = []ParamIn{ParamPath, ParamQuery, ParamHeader, ParamCookie}
+
+// inputSlots returns slots of typed input fields and index of field of each slot
+func inputSlots(Type reflect.Type) ([]paramSlot, []int, error) {
+	if Type.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("invalid input type (%s), must be struct", Type)
+	}
+
+	slots := make([]paramSlot, 0, Type.NumField())
+	fields := make([]int, 0, Type.NumField())
+
+	for i := 0; i < Type.NumField(); i++ {
+		field := Type.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		slot := paramSlot{Type: field.Type}
+
+		// untagged fields of types without spec are named after field, not after their type
+		if extractSpec(field.Type) == nil {
+			slot.override.spec = &Spec{
+				Name:        field.Name,
+				Description: field.Tag.Get("description"),
+			}
+		}
+
+		for _, in := range paramTags {
+			name, has := field.Tag.Lookup(string(in))
+
+			if !has {
+				continue
+			}
+
+			if name == "" {
+				name = field.Name
+			}
+
+			required, _ := strconv.ParseBool(field.Tag.Get("required"))
+
+			slot.override = paramOverride{
+				spec: &Spec{
+					Name:        name,
+					Required:    required || in == ParamPath,
+					Description: field.Tag.Get("description"),
+				},
+				in: in,
+			}
+			break
+		}
+
+		slots = append(slots, slot)
+		fields = append(fields, i)
+	}
+
+	return slots, fields, nil
+}
+
+// Handle registers typed endpoint, signature is checked by compiler and dispatch does not use reflect.Value.Call
+func Handle[In, Out any](r *Router, method Method, prefix string, fn Handler[In, Out], spec RouteSpec) httprouter.Handle {
+	api := r.api
+	fullPath, spec := r.resolve(prefix, spec)
+
+	endpointMethod, fields, binder, err := newTypedEndpointMethod[In, Out](api, method, fullPath, fn, spec)
+
+	if err != nil {
+		api.addRegistrationError(fullPath, method, getFunctionName(fn), err)
+		return invalidRouteHandle(api)
+	}
+
+	endpointMethod.Handler = func(
+		w http.ResponseWriter,
+		req *http.Request,
+		params httprouter.Params,
+	) {
+		out, response, ok := binder.bind(w, req, params)
+
+		if !ok {
+			return
+		}
+
+		var in In
+		inValue := reflect.ValueOf(&in).Elem()
+
+		for index, value := range out {
+			inValue.Field(fields[index]).Set(value)
+		}
+
+		ctx, span := StartSpan(req.Context(), "call")
+		result, err := fn(ctx, in)
+		span.Finish()
+
+		if !isNilError(err) {
+			writeError(w, req, api.errorHandler, err)
+			return
+		}
+
+		writeJSON(w, response.Status, response.Headers, result)
+	}
+
+	if err := registerEndpoint(api, fullPath, &endpointMethod); err != nil {
+		api.addRegistrationError(fullPath, method, getFunctionName(fn), err)
+		return invalidRouteHandle(api)
+	}
+
+	return endpointMethod.Handler
+}
+
+func newTypedEndpointMethod[In, Out any](
+	api *API,
+	method Method,
+	prefix string,
+	fn Handler[In, Out],
+	spec RouteSpec,
+) (EndpointMethod, []int, *requestBinder, error) {
+
+	slots, fields, err := inputSlots(GetType[In]())
+
+	if err != nil {
+		return EndpointMethod{}, nil, nil, err
+	}
+
+	endpointMethod, err := newEndpointSpec(method, reflect.TypeOf(fn), spec)
+
+	if err != nil {
+		return EndpointMethod{}, nil, nil, err
+	}
+
+	endpointMethod.function = getFunctionName(fn)
+
+	params, err := registerInputs(api, getFunctionName(fn), prefix, slots, &endpointMethod)
+
+	if err := errors.Join(err, registerOutput(api, GetType[Out](), &endpointMethod)); err != nil {
+		return EndpointMethod{}, nil, nil, err
+	}
+
+	return endpointMethod, fields, newRequestBinder(api, params, spec.Body), nil
+}
+
+func Get[In, Out any](r *Router, prefix string, fn Handler[In, Out], spec RouteSpec) httprouter.Handle {
+	return Handle(r, MethodGet, prefix, fn, spec)
+}
+
+func Post[In, Out any](r *Router, prefix string, fn Handler[In, Out], spec RouteSpec) httprouter.Handle {
+	return Handle(r, MethodPost, prefix, fn, spec)
+}
+
+func Put[In, Out any](r *Router, prefix string, fn Handler[In, Out], spec RouteSpec) httprouter.Handle {
+	return Handle(r, MethodPut, prefix, fn, spec)
+}
+
+func Options[In, Out any](r *Router, prefix string, fn Handler[In, Out], spec RouteSpec) httprouter.Handle {
+	return Handle(r, MethodOptions, prefix, fn, spec)
+}
+
+// isNilError reports if err is nil, including nil pointers stored in error interface
+func isNilError(err error) bool {
+	if err == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(err)
+
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
@@ -0,0 +1,207 @@
+package goapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/masnyjimmy/goapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type GetUserInput struct {
+	ID      int64    `path:"id"`
+	Fields  []string `query:"fields"`
+	Token   string   `header:"X-Token" required:"true"`
+	Verbose Verbose
+}
+
+type UpdateUserInput struct {
+	ID   int64 `path:"id"`
+	User User
+	R    goapi.Response
+}
+
+func TestTypedGet(t *testing.T) {
+	router := httprouter.New()
+	api := goapi.NewAPI(router, goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	goapi.Get(&appRouter, "/users/:id", func(ctx context.Context, in GetUserInput) (User, error) {
+		if !in.Verbose {
+			return User{}, goapi.NewAPIError(http.StatusBadRequest, "verbose required", nil)
+		}
+		return User{ID: in.ID, Name: in.Token, Fields: in.Fields}, nil
+	}, goapi.RouteSpec{})
+
+	params := api.Endpoints[0].Methods[0].Parameters
+
+	assert.Equal(t, "id", params[0].Name)
+	assert.Equal(t, goapi.ParamPath, params[0].In)
+	assert.True(t, params[0].Required)
+	assert.Equal(t, goapi.ParamHeader, params[2].In)
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/users/7?fields=name&verbose=true", nil)
+	req.Header.Set("X-Token", "secret")
+	router.ServeHTTP(recorder, req)
+
+	var user User
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &user))
+	assert.Equal(t, User{ID: 7, Name: "secret", Fields: []string{"name"}}, user)
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/users/7", nil)
+	req.Header.Set("X-Token", "secret")
+	router.ServeHTTP(recorder, req)
+
+	assert.Exactly(t, http.StatusBadRequest, recorder.Code, "Typed error not handled")
+}
+
+func TestTypedPut(t *testing.T) {
+	router := httprouter.New()
+	api := goapi.NewAPI(router, goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	goapi.Put(&appRouter, "/users/:id", func(ctx context.Context, in UpdateUserInput) (User, error) {
+		in.R.Status = http.StatusAccepted
+		in.User.ID = in.ID
+		return in.User, nil
+	}, goapi.RouteSpec{})
+
+	assert.Equal(t, "User", api.Endpoints[0].Methods[0].RequestBody)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("PUT", "/users/3", bytes.NewReader([]byte(`{"name":"jane"}`))))
+
+	assert.Exactly(t, http.StatusAccepted, recorder.Code)
+
+	var user User
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &user))
+	assert.Equal(t, User{ID: 3, Name: "jane"}, user)
+}
+
+type ShopError struct {
+	Code string
+}
+
+func (err *ShopError) Error() string {
+	return err.Code
+}
+
+type ShopErrorBody struct {
+	Code string `json:"code"`
+}
+
+func newShopAPI(adapt bool) goapi.API {
+	api := goapi.NewAPI(httprouter.New(), func(r goapi.Response, req *http.Request, err *ShopError) ShopErrorBody {
+		r.Status = http.StatusConflict
+		return ShopErrorBody{Code: err.Code}
+	}, goapi.AppMeta{})
+
+	if adapt {
+		goapi.AdaptErrors(&api, func(err goapi.APIError) *ShopError {
+			return &ShopError{Code: "request " + strconv.Itoa(err.StatusCode)}
+		})
+	}
+
+	appRouter := api.Router()
+
+	goapi.Put(&appRouter, "/users/:id", func(ctx context.Context, in UpdateUserInput) (User, error) {
+		if in.User.Name == "" {
+			return User{}, errors.New("name missing")
+		}
+		return in.User, nil
+	}, goapi.RouteSpec{})
+
+	return api
+}
+
+func TestTypedCustomErrors(t *testing.T) {
+	api := newShopAPI(false)
+
+	recorder := httptest.NewRecorder()
+	api.Handler().ServeHTTP(recorder, httptest.NewRequest("PUT", "/users/3", strings.NewReader(`{"name":`)))
+
+	assert.Exactly(t, http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(t, `{"detail": "invalid request body: unexpected EOF"}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	api.Handler().ServeHTTP(recorder, httptest.NewRequest("PUT", "/users/3", strings.NewReader(`{}`)))
+
+	assert.Exactly(t, http.StatusInternalServerError, recorder.Code, "Unhandled error not reported")
+
+	document, err := api.SpecDocument()
+	assert.NoError(t, err)
+
+	responses := document.Paths["/users/:id"]["put"].Responses
+	assert.Equal(t, "#/components/schemas/DefaultErrorType", responses["400"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/ShopErrorBody", responses["404"].Content["application/json"].Schema.Ref)
+	assert.Len(t, responses["500"].Content["application/json"].Schema.OneOf, 2)
+}
+
+func TestTypedAdaptErrors(t *testing.T) {
+	api := newShopAPI(true)
+
+	recorder := httptest.NewRecorder()
+	api.Handler().ServeHTTP(recorder, httptest.NewRequest("PUT", "/users/3", strings.NewReader(`{"name":`)))
+
+	assert.Exactly(t, http.StatusConflict, recorder.Code)
+	assert.JSONEq(t, `{"code": "request 400"}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	api.Handler().ServeHTTP(recorder, httptest.NewRequest("PUT", "/users/3", strings.NewReader(`{}`)))
+
+	assert.JSONEq(t, `{"code": "request 500"}`, recorder.Body.String())
+
+	document, err := api.SpecDocument()
+	assert.NoError(t, err)
+
+	responses := document.Paths["/users/:id"]["put"].Responses
+	assert.Equal(t, "#/components/schemas/ShopErrorBody", responses["400"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/ShopErrorBody", responses["500"].Content["application/json"].Schema.Ref)
+	assert.NotContains(t, document.Components.Schemas, "DefaultErrorType")
+}
+
+type UserPage struct {
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+}
+
+type ListUsersInput struct {
+	Page  int
+	Limit int `description:"page size"`
+}
+
+func TestTypedUntaggedFields(t *testing.T) {
+	router := httprouter.New()
+	api := goapi.NewAPI(router, goapi.DefaultErrorHandler(), goapi.AppMeta{})
+	appRouter := api.Router()
+
+	goapi.Get(&appRouter, "/users", func(ctx context.Context, in ListUsersInput) (UserPage, error) {
+		return UserPage{Page: in.Page, Limit: in.Limit}, nil
+	}, goapi.RouteSpec{})
+
+	assert.NoError(t, api.Validate())
+
+	params := api.Endpoints[0].Methods[0].Parameters
+
+	if assert.Len(t, params, 2) {
+		assert.Equal(t, "Page", params[0].Name)
+		assert.Equal(t, goapi.ParamQuery, params[0].In)
+		assert.Equal(t, "Limit", params[1].Name)
+		assert.Equal(t, "page size", params[1].Description)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/users?Page=2&Limit=10", nil))
+
+	assert.JSONEq(t, `{"page": 2, "limit": 10}`, recorder.Body.String())
+}
@@ -1,5 +1,7 @@
 package goapi
 
+import "time"
+
 type Email string
 
 func (Email) Format() string {
@@ -11,3 +13,19 @@ type Datetime string
 func (Datetime) Format() string {
 	return "date-time"
 }
+
+// UnmarshalText accepts only RFC 3339 date-time values
+func (d *Datetime) UnmarshalText(text []byte) error {
+	if _, err := time.Parse(time.RFC3339, string(text)); err != nil {
+		return err
+	}
+
+	*d = Datetime(text)
+
+	return nil
+}
+
+func (d Datetime) Time() time.Time {
+	t, _ := time.Parse(time.RFC3339, string(d))
+	return t
+}